@@ -0,0 +1,289 @@
+// Package alert evaluates declarative rules against event snapshots and routes the
+// alerts they fire to sinks.
+//
+// Rules are written in YAML or JSON and name Event and Market fields directly:
+//
+//	rules:
+//	  - name: wide-nba-spread
+//	    scope: market
+//	    series: [nba]
+//	    conditions:
+//	      - {field: bestAsk, minus: bestBid, op: ">", value: 0.1}
+//	  - name: big-event-featured
+//	    conditions:
+//	      - {field: volume24hr, op: ">", value: 1000000}
+//	      - {field: featured, op: becomes, value: true}
+//	sinks:
+//	  - {name: console, type: stdout}
+//	  - {name: log, type: file, path: alerts.ndjson}
+//
+// An Engine fires a rule once per event or market when it starts to match, so a
+// market that stays wide for an hour produces one alert, not one per poll. An alert
+// no sink accepted is sent again on the next poll.
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+const defaultInterval = 30 * time.Second
+
+// Alert is one rule firing for an event or market
+type Alert struct {
+	Rule           string    `json:"rule"`
+	Time           time.Time `json:"time"`
+	EventID        string    `json:"event_id"`
+	EventTitle     string    `json:"event_title"`
+	MarketID       string    `json:"market_id,omitempty"`
+	MarketQuestion string    `json:"market_question,omitempty"`
+	// Values holds the fields the rule's conditions read, keyed as written in the rule
+	Values map[string]any `json:"values"`
+
+	Event  *polymarket_gamma.Event  `json:"-"`
+	Market *polymarket_gamma.Market `json:"-"`
+
+	// key identifies the rule and event or market, for the firing state
+	key string
+}
+
+type Config struct {
+	// Sinks adds sinks by name, alongside those declared in the rule set (optional)
+	Sinks map[string]Sink
+	// Interval is the time between polls in Run (default 30s)
+	Interval time.Duration
+	// OnError is called when a poll or a sink fails in Run; the engine keeps polling
+	// (optional)
+	OnError func(error)
+}
+
+// Engine evaluates a rule set against successive snapshots
+type Engine struct {
+	rules     []*rule
+	sinks     map[string]Sink
+	sinkNames []string
+	config    Config
+
+	mu              sync.Mutex
+	firing          map[string]bool
+	previousEvents  map[string]polymarket_gamma.Event
+	previousMarkets map[string]polymarket_gamma.Market
+}
+
+// NewEngine compiles rules, failing on unknown fields, operators or sinks
+func NewEngine(rules *RuleSet, config *Config) (*Engine, error) {
+	if rules == nil {
+		rules = &RuleSet{}
+	}
+	if config == nil {
+		config = &Config{}
+	}
+
+	engine := &Engine{config: *config, sinks: map[string]Sink{}, firing: map[string]bool{}}
+	if engine.config.Interval <= 0 {
+		engine.config.Interval = defaultInterval
+	}
+
+	for _, sinkConfig := range rules.Sinks {
+		sink, err := NewSink(sinkConfig)
+		if err != nil {
+			return nil, err
+		}
+		engine.sinks[sinkConfig.Name] = sink
+	}
+	for name, sink := range config.Sinks {
+		engine.sinks[name] = sink
+	}
+	for name := range engine.sinks {
+		engine.sinkNames = append(engine.sinkNames, name)
+	}
+	sort.Strings(engine.sinkNames)
+
+	names := map[string]bool{}
+	for _, r := range rules.Rules {
+		compiled, err := compileRule(r)
+		if err != nil {
+			return nil, err
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicate rule %q", r.Name)
+		}
+		names[r.Name] = true
+
+		for _, sink := range r.Sinks {
+			if _, ok := engine.sinks[sink]; !ok {
+				return nil, fmt.Errorf("rule %q: unknown sink %q", r.Name, sink)
+			}
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+
+	return engine, nil
+}
+
+// Evaluate checks every rule against a snapshot and sends the alerts that fire.
+// Snapshots should cover the same query each time: an event or market missing from
+// one counts as no longer matching, so it fires again when it reappears. An alert
+// that none of its sinks accepted is not marked as firing, so it is sent again with
+// the next snapshot it matches. The error joins any sink failures; the alerts are
+// returned regardless.
+func (e *Engine) Evaluate(ctx context.Context, events []polymarket_gamma.Event) ([]Alert, error) {
+	e.mu.Lock()
+	alerts := e.evaluate(events)
+	e.mu.Unlock()
+
+	var (
+		errs []error
+		sent []string
+	)
+	for _, alert := range alerts {
+		names := e.sinksFor(alert.Rule)
+		delivered := len(names) == 0
+		for _, name := range names {
+			if err := e.sinks[name].Send(ctx, alert); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send alert %q to sink %q: %w", alert.Rule, name, err))
+				continue
+			}
+			delivered = true
+		}
+		if delivered {
+			sent = append(sent, alert.key)
+		}
+	}
+
+	e.mu.Lock()
+	for _, key := range sent {
+		e.firing[key] = true
+	}
+	e.mu.Unlock()
+
+	return alerts, errors.Join(errs...)
+}
+
+// Close closes the sinks that hold resources, such as webhook dead-letter files
+func (e *Engine) Close() error {
+	var errs []error
+	for _, name := range e.sinkNames {
+		if closer, ok := e.sinks[name].(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run polls query until ctx is done, evaluating each snapshot
+func (e *Engine) Run(ctx context.Context, api polymarket_gamma.EventsAPI, query polymarket_gamma.WatchQuery) error {
+	if query == nil {
+		return errors.New("alert engine has no query")
+	}
+
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := query(ctx, api)
+		if err == nil {
+			_, err = e.Evaluate(ctx, events)
+		}
+		if err != nil && ctx.Err() == nil && e.config.OnError != nil {
+			e.config.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Engine) evaluate(events []polymarket_gamma.Event) []Alert {
+	now := time.Now()
+	firing := map[string]bool{}
+	var alerts []Alert
+
+	// Keys only stay firing here if they already were; new alerts are marked by
+	// Evaluate once a sink has accepted them
+	fire := func(r *rule, key string, event *polymarket_gamma.Event, market *polymarket_gamma.Market) {
+		if e.firing[key] {
+			firing[key] = true
+			return
+		}
+
+		alert := Alert{
+			Rule:       r.Name,
+			Time:       now,
+			EventID:    event.ID,
+			EventTitle: event.Title,
+			Values:     r.values(event, market),
+			Event:      event,
+			Market:     market,
+			key:        key,
+		}
+		if market != nil {
+			alert.MarketID = market.ID
+			alert.MarketQuestion = market.Question
+		}
+		alerts = append(alerts, alert)
+	}
+
+	for _, r := range e.rules {
+		for i := range events {
+			event := &events[i]
+			if !r.matchEvent(event) {
+				continue
+			}
+
+			var previousEvent *polymarket_gamma.Event
+			if previous, ok := e.previousEvents[event.ID]; ok {
+				previousEvent = &previous
+			}
+
+			if r.Scope == ScopeEvent {
+				if r.match(event, nil, previousEvent, nil) {
+					fire(r, r.Name+"\x00event\x00"+event.ID, event, nil)
+				}
+				continue
+			}
+
+			for j := range event.Markets {
+				market := &event.Markets[j]
+				var previousMarket *polymarket_gamma.Market
+				if previous, ok := e.previousMarkets[market.ID]; ok {
+					previousMarket = &previous
+				}
+				if r.match(event, market, previousEvent, previousMarket) {
+					fire(r, r.Name+"\x00market\x00"+market.ID, event, market)
+				}
+			}
+		}
+	}
+
+	e.firing = firing
+	e.previousEvents = make(map[string]polymarket_gamma.Event, len(events))
+	e.previousMarkets = map[string]polymarket_gamma.Market{}
+	for _, event := range events {
+		e.previousEvents[event.ID] = event
+		for _, market := range event.Markets {
+			e.previousMarkets[market.ID] = market
+		}
+	}
+	return alerts
+}
+
+// sinksFor returns the names of the sinks a rule's alerts go to
+func (e *Engine) sinksFor(ruleName string) []string {
+	for _, r := range e.rules {
+		if r.Name == ruleName && len(r.Sinks) > 0 {
+			return r.Sinks
+		}
+	}
+	return e.sinkNames
+}
@@ -0,0 +1,245 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRules = `
+rules:
+  - name: wide-nba-spread
+    scope: market
+    series: [nba]
+    conditions:
+      - {field: bestAsk, minus: bestBid, op: ">", value: 0.1}
+    sinks: [recorder]
+  - name: big-event-featured
+    conditions:
+      - {field: Volume24hr, op: ">", value: 1000000}
+      - {field: featured, op: becomes, value: true}
+sinks:
+  - {name: log, type: file, path: %s}
+`
+
+type recorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *recorder) Send(ctx context.Context, alert Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func nbaEvent(bid float64) polymarket_gamma.Event {
+	return polymarket_gamma.Event{
+		ID:         "1",
+		Title:      "Lakers vs Celtics",
+		SeriesSlug: "nba",
+		Markets: []polymarket_gamma.Market{
+			{ID: "m1", Question: "Lakers win?", BestBid: bid, BestAsk: 0.6},
+			{ID: "m2", Question: "Over 210.5?", BestBid: 0.49, BestAsk: 0.51},
+		},
+	}
+}
+
+func bigEvent(featured bool) polymarket_gamma.Event {
+	return polymarket_gamma.Event{ID: "2", Title: "Election", Volume24hr: 2_000_000, Featured: featured}
+}
+
+func TestEngineFiresOncePerTransition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.ndjson")
+	rules, err := ParseRules([]byte(strings.Replace(testRules, "%s", path, 1)))
+	require.NoError(t, err)
+
+	sink := &recorder{}
+	engine, err := NewEngine(rules, &Config{Sinks: map[string]Sink{"recorder": sink}})
+	require.NoError(t, err)
+
+	fired := func(events ...polymarket_gamma.Event) []string {
+		alerts, err := engine.Evaluate(context.Background(), events)
+		require.NoError(t, err)
+		var names []string
+		for _, alert := range alerts {
+			names = append(names, alert.Rule+"/"+alert.EventID+"/"+alert.MarketID)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"wide-nba-spread/1/m1"}, fired(nbaEvent(0.45), bigEvent(false)))
+	assert.Nil(t, fired(nbaEvent(0.45), bigEvent(false)), "still matching, so no new alert")
+	assert.Equal(t, []string{"big-event-featured/2/"}, fired(nbaEvent(0.45), bigEvent(true)))
+	assert.Nil(t, fired(nbaEvent(0.55), bigEvent(true)), "spread narrowed, featured unchanged")
+	assert.Equal(t, []string{"wide-nba-spread/1/m1"}, fired(nbaEvent(0.4), bigEvent(true)))
+
+	require.Len(t, sink.alerts, 3)
+	assert.Equal(t, "Lakers win?", sink.alerts[0].MarketQuestion)
+	assert.Equal(t, map[string]any{"bestAsk": 0.6, "bestBid": 0.45}, sink.alerts[0].Values)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1, "the spread rule routes to the recorder only")
+	var logged Alert
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &logged))
+	assert.Equal(t, "big-event-featured", logged.Rule)
+	assert.Equal(t, "Election", logged.EventTitle)
+	assert.Equal(t, true, logged.Values["featured"])
+}
+
+// flakySink fails its first failures sends
+type flakySink struct {
+	recorder
+	failures int
+}
+
+func (f *flakySink) Send(ctx context.Context, alert Alert) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("connection refused")
+	}
+	return f.recorder.Send(ctx, alert)
+}
+
+func TestEngineRetriesUndeliveredAlerts(t *testing.T) {
+	rules, err := ParseRules([]byte(`
+rules:
+  - name: wide-nba-spread
+    scope: market
+    conditions:
+      - {field: bestAsk, minus: bestBid, op: ">", value: 0.1}
+`))
+	require.NoError(t, err)
+
+	sink := &flakySink{failures: 1}
+	engine, err := NewEngine(rules, &Config{Sinks: map[string]Sink{"flaky": sink}})
+	require.NoError(t, err)
+
+	alerts, err := engine.Evaluate(context.Background(), []polymarket_gamma.Event{nbaEvent(0.45)})
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Len(t, alerts, 1)
+
+	alerts, err = engine.Evaluate(context.Background(), []polymarket_gamma.Event{nbaEvent(0.45)})
+	require.NoError(t, err)
+	assert.Len(t, alerts, 1, "the failed delivery is retried")
+
+	alerts, err = engine.Evaluate(context.Background(), []polymarket_gamma.Event{nbaEvent(0.45)})
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+	assert.Len(t, sink.alerts, 1)
+}
+
+func TestEngineConditions(t *testing.T) {
+	rules, err := ParseRules([]byte(`{
+		"rules": [
+			{"name": "sports", "conditions": [{"field": "category", "op": "in", "value": ["Sports", "Esports"]}]},
+			{"name": "ending", "conditions": [{"field": "endDate", "op": "<", "value": "2026-11-01T00:00:00Z"}]},
+			{"name": "volume", "scope": "market", "conditions": [
+				{"field": "volume", "op": ">=", "value": 500},
+				{"field": "event.title", "op": "contains", "value": "Cup"}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(rules, nil)
+	require.NoError(t, err)
+
+	event := polymarket_gamma.Event{
+		ID:       "3",
+		Title:    "World Cup",
+		Category: "Sports",
+		Markets:  []polymarket_gamma.Market{{ID: "m3", Volume: "750.5"}, {ID: "m4", Volume: "12"}},
+	}
+	alerts, err := engine.Evaluate(context.Background(), []polymarket_gamma.Event{event})
+	require.NoError(t, err)
+
+	var names []string
+	for _, alert := range alerts {
+		names = append(names, alert.Rule+"/"+alert.MarketID)
+	}
+	assert.Equal(t, []string{"sports/", "ending/", "volume/m3"}, names)
+}
+
+func TestCompileErrors(t *testing.T) {
+	for rules, want := range map[string]string{
+		`{rules: [{name: a, scope: market, conditions: [{field: bestAks, op: ">", value: 1}]}]}`: `rule "a": condition 1: unknown field "bestAks" on Market (did you mean "bestAsk"?)`,
+		`{rules: [{name: a, conditions: [{field: featured, op: ">", value: 1}]}]}`:               `rule "a": condition 1: > does not apply to boolean field "featured"`,
+		`{rules: [{name: a, conditions: [{field: volume, op: "~", value: 1}]}]}`:                 `rule "a": condition 1: unknown operator "~"`,
+		`{rules: [{name: a, conditions: [{field: volume, op: ">", value: lots}]}]}`:              `rule "a": condition 1: value lots is not a number`,
+		`{rules: [{name: a, conditions: [{field: markets, op: "==", value: 1}]}]}`:               `rule "a": condition 1: field "markets" cannot be compared`,
+		`{rules: [{name: a, scope: series}]}`:                                                    `rule "a": unknown scope "series"`,
+		`{rules: [{name: a, sinks: [pager]}]}`:                                                   `rule "a": unknown sink "pager"`,
+		`{rules: [{name: a}, {name: a}]}`:                                                        `duplicate rule "a"`,
+		`{sinks: [{name: hook, type: webhook}]}`:                                                 `sink "hook" has no URL`,
+	} {
+		parsed, err := ParseRules([]byte(rules))
+		require.NoError(t, err, rules)
+		_, err = NewEngine(parsed, nil)
+		assert.EqualError(t, err, want, rules)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	var (
+		body      []byte
+		timestamp string
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		timestamp = r.Header.Get(webhook.TimestampHeader)
+		signature = r.Header.Get(webhook.SignatureHeader)
+	}))
+	defer server.Close()
+
+	alert := Alert{
+		Rule:           "wide-nba-spread",
+		EventID:        "1",
+		EventTitle:     "Lakers vs Celtics",
+		MarketID:       "m1",
+		MarketQuestion: "Lakers win?",
+		Values:         map[string]any{"bestBid": 0.45, "bestAsk": 0.6},
+	}
+
+	sink, err := NewSink(SinkConfig{Name: "hook", Type: "webhook", URL: server.URL, Secret: "s3cret"})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), alert))
+	assert.True(t, webhook.Verify("s3cret", timestamp, body, signature))
+	assert.Contains(t, string(body), `"market_id":"m1"`)
+
+	// Without a secret nothing is signed, and failures come back to the engine
+	var hits int
+	unsigned := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		signature = r.Header.Get(webhook.SignatureHeader)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer unsigned.Close()
+
+	sink, err = NewSink(SinkConfig{Name: "open", Type: "webhook", URL: unsigned.URL})
+	require.NoError(t, err)
+	assert.ErrorContains(t, sink.Send(context.Background(), alert), "failed to deliver to open")
+	assert.Equal(t, 1, hits)
+	assert.Empty(t, signature)
+
+	var out strings.Builder
+	require.NoError(t, NewWriterSink(&out).Send(context.Background(), alert))
+	assert.Equal(t, "0001-01-01T00:00:00Z wide-nba-spread event=1 \"Lakers vs Celtics\" market=m1 \"Lakers win?\" bestAsk=0.6 bestBid=0.45\n", out.String())
+}
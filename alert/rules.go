@@ -0,0 +1,383 @@
+package alert
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/internal/fields"
+	"gopkg.in/yaml.v3"
+)
+
+// Scope is what a rule is evaluated against
+type Scope string
+
+const (
+	ScopeEvent  Scope = "event"
+	ScopeMarket Scope = "market"
+)
+
+// RuleSet is the declarative configuration read from a rules file
+type RuleSet struct {
+	Rules []Rule       `json:"rules" yaml:"rules"`
+	Sinks []SinkConfig `json:"sinks" yaml:"sinks"`
+}
+
+// Rule fires for each event, or market, that satisfies every condition.
+//
+// A rule fires once when an event or market starts to match, and not again until it
+// has stopped matching in a later snapshot.
+type Rule struct {
+	Name string `json:"name" yaml:"name"`
+	// Scope is "event" (the default) or "market"
+	Scope Scope `json:"scope" yaml:"scope"`
+	// Series and Tags limit the rule to events with one of these series or tag slugs
+	Series []string `json:"series" yaml:"series"`
+	Tags   []string `json:"tags" yaml:"tags"`
+	// Conditions must all hold
+	Conditions []Condition `json:"conditions" yaml:"conditions"`
+	// Sinks names the sinks alerts go to (default all)
+	Sinks []string `json:"sinks" yaml:"sinks"`
+}
+
+// Condition compares a field with a value.
+//
+// Field is an Event or Market field name in the scope of the rule, as in the API's
+// JSON ("bestAsk") or Go ("BestAsk"). Market rules reach their event's fields with
+// an "event." prefix ("event.seriesSlug").
+//
+// Op is one of ==, !=, <, <=, >, >=, contains, in or becomes. "becomes" holds when the
+// field equals Value now but did not in the previous snapshot, such as featured
+// becoming true. Times compare with RFC 3339 values.
+type Condition struct {
+	Field string `json:"field" yaml:"field"`
+	// Minus is an optional numeric field subtracted from Field before comparing, so
+	// {field: bestAsk, minus: bestBid, op: ">", value: 0.1} is a spread wider than 0.1
+	Minus string `json:"minus,omitempty" yaml:"minus,omitempty"`
+	Op    string `json:"op" yaml:"op"`
+	Value any    `json:"value" yaml:"value"`
+}
+
+// ParseRules parses a rule set from YAML or JSON
+func ParseRules(data []byte) (*RuleSet, error) {
+	var rules RuleSet
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// LoadRules reads a rule set from a YAML or JSON file
+func LoadRules(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	return ParseRules(data)
+}
+
+type valueKind int
+
+const (
+	kindNumber valueKind = iota
+	kindString
+	kindBool
+	kindTime
+)
+
+var (
+	eventType  = reflect.TypeOf(polymarket_gamma.Event{})
+	marketType = reflect.TypeOf(polymarket_gamma.Market{})
+)
+
+// getter reads a field from the event or market being evaluated
+type getter func(event *polymarket_gamma.Event, market *polymarket_gamma.Market) any
+
+type rule struct {
+	Rule
+	conditions []condition
+}
+
+type condition struct {
+	Condition
+	kind  valueKind
+	field getter
+	minus getter
+	value any
+}
+
+func compileRule(r Rule) (*rule, error) {
+	if r.Name == "" {
+		return nil, fmt.Errorf("rule has no name")
+	}
+	if r.Scope == "" {
+		r.Scope = ScopeEvent
+	}
+	if r.Scope != ScopeEvent && r.Scope != ScopeMarket {
+		return nil, fmt.Errorf("rule %q: unknown scope %q", r.Name, r.Scope)
+	}
+
+	compiled := &rule{Rule: r}
+	for i, c := range r.Conditions {
+		cond, err := compileCondition(r.Scope, c)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: condition %d: %w", r.Name, i+1, err)
+		}
+		compiled.conditions = append(compiled.conditions, cond)
+	}
+	return compiled, nil
+}
+
+func compileCondition(scope Scope, c Condition) (condition, error) {
+	get, t, err := resolve(scope, c.Field)
+	if err != nil {
+		return condition{}, err
+	}
+
+	cond := condition{Condition: c, field: get}
+	switch {
+	case fields.IsTime(t):
+		cond.kind = kindTime
+	case t.Kind() == reflect.Bool:
+		cond.kind = kindBool
+	case t.Kind() == reflect.String:
+		cond.kind = kindString
+	case fields.IsNumeric(t):
+		cond.kind = kindNumber
+	default:
+		return condition{}, fmt.Errorf("field %q cannot be compared", c.Field)
+	}
+
+	if c.Minus != "" {
+		minus, minusType, err := resolve(scope, c.Minus)
+		if err != nil {
+			return condition{}, err
+		}
+		if !fields.IsNumeric(t) || !fields.IsNumeric(minusType) {
+			return condition{}, fmt.Errorf("%q minus %q needs numeric fields", c.Field, c.Minus)
+		}
+		cond.minus = minus
+		cond.kind = kindNumber
+	}
+
+	switch c.Op {
+	case "<", "<=", ">", ">=":
+		// Ordering a string field compares it as a number, for decimal strings
+		if cond.kind == kindString {
+			cond.kind = kindNumber
+		}
+		if cond.kind == kindBool {
+			return condition{}, fmt.Errorf("%s does not apply to boolean field %q", c.Op, c.Field)
+		}
+		cond.value, err = normalize(cond.kind, c.Value)
+	case "==", "!=", "becomes":
+		cond.value, err = normalize(cond.kind, c.Value)
+	case "contains":
+		if cond.kind != kindString {
+			return condition{}, fmt.Errorf("contains does not apply to non-string field %q", c.Field)
+		}
+		cond.value, err = normalize(kindString, c.Value)
+	case "in":
+		values, ok := c.Value.([]any)
+		if !ok {
+			return condition{}, fmt.Errorf("in needs a list of values")
+		}
+		var normalized []any
+		for _, v := range values {
+			n, err := normalize(cond.kind, v)
+			if err != nil {
+				return condition{}, err
+			}
+			normalized = append(normalized, n)
+		}
+		cond.value = normalized
+	default:
+		return condition{}, fmt.Errorf("unknown operator %q", c.Op)
+	}
+	if err != nil {
+		return condition{}, err
+	}
+	return cond, nil
+}
+
+// resolve finds name in the rule's scope
+func resolve(scope Scope, name string) (getter, reflect.Type, error) {
+	if rest, ok := strings.CutPrefix(name, "event."); ok {
+		field, err := fields.Lookup(eventType, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(event *polymarket_gamma.Event, _ *polymarket_gamma.Market) any {
+			return field.Get(reflect.ValueOf(event).Elem()).Interface()
+		}, field.Type, nil
+	}
+
+	if scope == ScopeEvent {
+		return resolve(scope, "event."+name)
+	}
+
+	field, err := fields.Lookup(marketType, strings.TrimPrefix(name, "market."))
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(_ *polymarket_gamma.Event, market *polymarket_gamma.Market) any {
+		return field.Get(reflect.ValueOf(market).Elem()).Interface()
+	}, field.Type, nil
+}
+
+// normalize converts a rule value, as decoded from YAML or JSON, to the form values
+// of kind are compared in
+func normalize(kind valueKind, v any) (any, error) {
+	switch kind {
+	case kindNumber:
+		if n, ok := fields.Number(v); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("value %v is not a number", v)
+	case kindBool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("value %v is not a boolean", v)
+	case kindTime:
+		switch t := v.(type) {
+		case time.Time:
+			return t, nil
+		case string:
+			parsed, err := time.Parse(time.RFC3339, t)
+			if err != nil {
+				return nil, fmt.Errorf("value %q is not an RFC 3339 time", t)
+			}
+			return parsed, nil
+		}
+		return nil, fmt.Errorf("value %v is not a time", v)
+	default:
+		if v == nil {
+			return "", nil
+		}
+		return fmt.Sprint(v), nil
+	}
+}
+
+// matchEvent applies the rule's series and tag filters
+func (r *rule) matchEvent(event *polymarket_gamma.Event) bool {
+	if len(r.Tags) > 0 && !slices.ContainsFunc(event.Tags, func(tag polymarket_gamma.Tag) bool {
+		return slices.Contains(r.Tags, tag.Slug)
+	}) {
+		return false
+	}
+	if len(r.Series) > 0 && !slices.Contains(r.Series, event.SeriesSlug) && !slices.ContainsFunc(event.Series, func(series polymarket_gamma.Series) bool {
+		return slices.Contains(r.Series, series.Slug)
+	}) {
+		return false
+	}
+	return true
+}
+
+// match reports whether every condition holds. previousEvent and previousMarket are
+// the last snapshot, or nil when this is the first.
+func (r *rule) match(event *polymarket_gamma.Event, market *polymarket_gamma.Market, previousEvent *polymarket_gamma.Event, previousMarket *polymarket_gamma.Market) bool {
+	for _, c := range r.conditions {
+		if c.Op == "becomes" {
+			if previousEvent == nil || (r.Scope == ScopeMarket && previousMarket == nil) {
+				return false
+			}
+			if !c.holds(event, market) || c.holds(previousEvent, previousMarket) {
+				return false
+			}
+			continue
+		}
+		if !c.holds(event, market) {
+			return false
+		}
+	}
+	return true
+}
+
+// values returns the fields the rule reads, keyed as written in the conditions
+func (r *rule) values(event *polymarket_gamma.Event, market *polymarket_gamma.Market) map[string]any {
+	values := make(map[string]any, len(r.conditions))
+	for _, c := range r.conditions {
+		values[c.Field] = c.field(event, market)
+		if c.minus != nil {
+			values[c.Minus] = c.minus(event, market)
+		}
+	}
+	return values
+}
+
+func (c *condition) holds(event *polymarket_gamma.Event, market *polymarket_gamma.Market) bool {
+	actual, ok := c.actual(event, market)
+	if !ok {
+		return false
+	}
+
+	switch c.Op {
+	case "==", "becomes":
+		return equal(actual, c.value)
+	case "!=":
+		return !equal(actual, c.value)
+	case "contains":
+		return strings.Contains(actual.(string), c.value.(string))
+	case "in":
+		return slices.ContainsFunc(c.value.([]any), func(v any) bool { return equal(actual, v) })
+	}
+
+	order := compare(actual, c.value)
+	switch c.Op {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+// actual reads the field, converted to the condition's kind
+func (c *condition) actual(event *polymarket_gamma.Event, market *polymarket_gamma.Market) (any, bool) {
+	v := c.field(event, market)
+	if c.kind != kindNumber {
+		return v, true
+	}
+
+	n, ok := fields.Number(v)
+	if !ok {
+		return nil, false
+	}
+	if c.minus != nil {
+		m, ok := fields.Number(c.minus(event, market))
+		if !ok {
+			return nil, false
+		}
+		n -= m
+	}
+	return n, true
+}
+
+func equal(a, b any) bool {
+	if t, ok := a.(time.Time); ok {
+		return t.Equal(b.(time.Time))
+	}
+	return a == b
+}
+
+func compare(a, b any) int {
+	if t, ok := a.(time.Time); ok {
+		return t.Compare(b.(time.Time))
+	}
+	x, y := a.(float64), b.(float64)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
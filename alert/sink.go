@@ -0,0 +1,154 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CalderWhite/polymarket-gamma-go/webhook"
+)
+
+// Sink receives fired alerts
+type Sink interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+// SinkConfig declares a sink in a rule set
+type SinkConfig struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is "stdout", "file" or "webhook"
+	Type string `json:"type" yaml:"type"`
+	// Path is the NDJSON file alerts are appended to, for file sinks
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// URL and Secret configure webhook sinks, which deliver through the webhook
+	// package: requests are signed when Secret is set, so webhook.Verify checks them
+	URL    string `json:"url,omitempty" yaml:"url,omitempty"`
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// DeadLetter is the NDJSON file undeliverable webhook alerts are appended to
+	// (optional)
+	DeadLetter string `json:"dead_letter,omitempty" yaml:"dead_letter,omitempty"`
+}
+
+// NewSink builds the sink a SinkConfig declares
+func NewSink(config SinkConfig) (Sink, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("sink has no name")
+	}
+
+	switch config.Type {
+	case "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case "file":
+		if config.Path == "" {
+			return nil, fmt.Errorf("sink %q has no path", config.Name)
+		}
+		return &FileSink{Path: config.Path}, nil
+	case "webhook":
+		if config.URL == "" {
+			return nil, fmt.Errorf("sink %q has no URL", config.Name)
+		}
+		dispatcher, err := webhook.New(&webhook.Config{
+			Subscribers:    []webhook.Subscriber{{Name: config.Name, URL: config.URL, Secret: config.Secret}},
+			DeadLetterPath: config.DeadLetter,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sink %q: %w", config.Name, err)
+		}
+		return NewWebhookSink(dispatcher), nil
+	default:
+		return nil, fmt.Errorf("sink %q has unknown type %q", config.Name, config.Type)
+	}
+}
+
+// WriterSink writes one human-readable line per alert
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Send(ctx context.Context, alert Alert) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s event=%s %q", alert.Time.Format(time.RFC3339), alert.Rule, alert.EventID, alert.EventTitle)
+	if alert.MarketID != "" {
+		fmt.Fprintf(&b, " market=%s %q", alert.MarketID, alert.MarketQuestion)
+	}
+
+	names := make([]string, 0, len(alert.Values))
+	for name := range alert.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := alert.Values[name]
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339)
+		}
+		fmt.Fprintf(&b, " %s=%v", name, value)
+	}
+	b.WriteByte('\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.WriteString(s.w, b.String()); err != nil {
+		return fmt.Errorf("failed to write alert: %w", err)
+	}
+	return nil
+}
+
+// FileSink appends alerts to an NDJSON file
+type FileSink struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileSink) Send(ctx context.Context, alert Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open alert file: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write alert: %w", err)
+	}
+	return file.Close()
+}
+
+// WebhookSink POSTs each alert as JSON through a webhook.Dispatcher, so requests are
+// signed when a secret is set, retried on failure and dead-lettered like watcher
+// notifications. Send fails when the alert was not delivered.
+type WebhookSink struct {
+	dispatcher *webhook.Dispatcher
+}
+
+// NewWebhookSink sends alerts to every subscriber of dispatcher
+func NewWebhookSink(dispatcher *webhook.Dispatcher) *WebhookSink {
+	return &WebhookSink{dispatcher: dispatcher}
+}
+
+func (s *WebhookSink) Send(ctx context.Context, alert Alert) error {
+	return s.dispatcher.Send(ctx, alert)
+}
+
+// Close closes the dispatcher's dead-letter file
+func (s *WebhookSink) Close() error {
+	return s.dispatcher.Close()
+}
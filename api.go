@@ -0,0 +1,57 @@
+package polymarket_gamma
+
+import "context"
+
+// EventsAPI is the set of event queries supported by *Client. Code that depends on
+// this interface instead of *Client can be unit-tested with a fake such as
+// gammatest.MockEventsAPI.
+type EventsAPI interface {
+	GetEventsByIDs(ids []int) (*GetEventsResponse, error)
+	GetEventsByIDsContext(ctx context.Context, ids []int) (*GetEventsResponse, error)
+	GetEventsByPage(offset, limit int, ascending bool) (*GetEventsResponse, error)
+	GetEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error)
+	GetActiveEventsByPage(offset, limit int, ascending bool) (*GetEventsResponse, error)
+	GetActiveEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error)
+	GetEventsByUpdatedAtPage(offset, limit int, ascending bool) (*GetEventsResponse, error)
+	GetEventsByUpdatedAtPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error)
+	GetEventsByKeysetPage(afterCursor string, limit int) (*GetEventsKeysetResponse, error)
+	GetEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*GetEventsKeysetResponse, error)
+	GetActiveEventsByKeysetPage(afterCursor string, limit int) (*GetEventsKeysetResponse, error)
+	GetActiveEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*GetEventsKeysetResponse, error)
+	QueryEvents(query *EventQuery) (*GetEventsResponse, error)
+	QueryEventsContext(ctx context.Context, query *EventQuery) (*GetEventsResponse, error)
+}
+
+// MarketsAPI is the set of market queries supported by *Client
+type MarketsAPI interface {
+	GetMarketsByIDs(ids []int) (*GetMarketsResponse, error)
+	GetMarketsByIDsContext(ctx context.Context, ids []int) (*GetMarketsResponse, error)
+	QueryMarkets(query *MarketQuery) (*GetMarketsResponse, error)
+	QueryMarketsContext(ctx context.Context, query *MarketQuery) (*GetMarketsResponse, error)
+}
+
+// TagsAPI is the set of tag queries supported by *Client
+type TagsAPI interface {
+	GetTagsByPage(offset, limit int) (*GetTagsResponse, error)
+	GetTagsByPageContext(ctx context.Context, offset, limit int) (*GetTagsResponse, error)
+}
+
+// SeriesAPI is the set of series queries supported by *Client
+type SeriesAPI interface {
+	GetSeriesByPage(offset, limit int) (*GetSeriesResponse, error)
+	GetSeriesByPageContext(ctx context.Context, offset, limit int) (*GetSeriesResponse, error)
+}
+
+// SearchAPI is the full-text search supported by *Client
+type SearchAPI interface {
+	Search(query string, limitPerType int) (*SearchResponse, error)
+	SearchContext(ctx context.Context, query string, limitPerType int) (*SearchResponse, error)
+}
+
+var (
+	_ EventsAPI  = (*Client)(nil)
+	_ MarketsAPI = (*Client)(nil)
+	_ TagsAPI    = (*Client)(nil)
+	_ SeriesAPI  = (*Client)(nil)
+	_ SearchAPI  = (*Client)(nil)
+)
@@ -0,0 +1,192 @@
+package polymarket_gamma
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ArchiveRecord is a raw API response exactly as Polymarket returned it (after gzip
+// decompression), along with where and when it was fetched
+type ArchiveRecord struct {
+	URL        string      `json:"url"`
+	Timestamp  time.Time   `json:"timestamp"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// Archiver persists raw responses. Archive is called for every response the client
+// receives, including non-200 responses, and must be safe for concurrent use. If it
+// returns an error the client call fails, so nothing is served that was not archived.
+type Archiver interface {
+	Archive(record *ArchiveRecord) error
+}
+
+// DirArchiver writes each response to its own pair of files in a directory: the
+// exact body bytes as <name>.body and everything else as <name>.json
+type DirArchiver struct {
+	dir string
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewDirArchiver creates dir if needed and returns an archiver writing into it
+func NewDirArchiver(dir string) (*DirArchiver, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	return &DirArchiver{dir: dir}, nil
+}
+
+func (a *DirArchiver) Archive(record *ArchiveRecord) error {
+	a.mu.Lock()
+	a.seq++
+	name := fmt.Sprintf("%s-%06d", record.Timestamp.UTC().Format("20060102T150405.000000000Z"), a.seq)
+	a.mu.Unlock()
+
+	metadata := *record
+	metadata.Body = nil
+	encoded, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive record: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(a.dir, name+".body"), record.Body, 0o644); err != nil {
+		return fmt.Errorf("failed to write archived body: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(a.dir, name+".json"), encoded, 0o644); err != nil {
+		return fmt.Errorf("failed to write archived metadata: %w", err)
+	}
+
+	return nil
+}
+
+// NDJSONArchiver appends one ArchiveRecord per line to a file (the body is base64
+// encoded, so bytes are kept exactly). Once the file reaches maxBytes it is renamed
+// with a timestamp suffix and a fresh file is started.
+type NDJSONArchiver struct {
+	path     string
+	maxBytes int64
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewNDJSONArchiver opens (or creates) path for appending. A maxBytes of zero
+// disables rotation.
+func NewNDJSONArchiver(path string, maxBytes int64) (*NDJSONArchiver, error) {
+	a := &NDJSONArchiver{
+		path:     path,
+		maxBytes: maxBytes,
+	}
+	if err := a.open(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *NDJSONArchiver) Archive(record *ArchiveRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive record: %w", err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return fmt.Errorf("archive %s is closed", a.path)
+	}
+
+	if a.maxBytes > 0 && a.size > 0 && a.size+int64(len(line)) > a.maxBytes {
+		if err := a.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := a.file.Write(line)
+	a.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write archive record: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the current archive file
+func (a *NDJSONArchiver) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
+
+func (a *NDJSONArchiver) open() error {
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat archive: %w", err)
+	}
+
+	a.file = file
+	a.size = stat.Size()
+	return nil
+}
+
+func (a *NDJSONArchiver) rotate() error {
+	if err := a.file.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	a.file = nil
+
+	rotated := fmt.Sprintf("%s.%s", a.path, time.Now().UTC().Format("20060102T150405.000000000Z"))
+	if err := os.Rename(a.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate archive: %w", err)
+	}
+
+	return a.open()
+}
+
+// ReadNDJSONArchive reads back every record written by an NDJSONArchiver to path
+func ReadNDJSONArchive(path string) ([]ArchiveRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	var records []ArchiveRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<30)
+	for scanner.Scan() {
+		var record ArchiveRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse archive record %d: %w", len(records), err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	return records, nil
+}
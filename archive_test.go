@@ -0,0 +1,184 @@
+package polymarket_gamma
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type archiverFunc func(record *ArchiveRecord) error
+
+func (f archiverFunc) Archive(record *ArchiveRecord) error {
+	return f(record)
+}
+
+func TestRetainRawBodyAndArchive(t *testing.T) {
+	body := `[{"id": "1", "title": "Raw Event", "unknownField": [1, 2, 3]}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "500" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	var records []*ArchiveRecord
+	client := NewClient(&ClientConfig{
+		BaseURL:       server.URL,
+		RetainRawBody: true,
+		Archiver: archiverFunc(func(record *ArchiveRecord) error {
+			records = append(records, record)
+			return nil
+		}),
+	})
+
+	response, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, body, string(response.Raw))
+
+	_, err = client.GetEventsByIDs([]int{500})
+	require.Error(t, err)
+
+	require.Len(t, records, 2)
+	assert.Equal(t, server.URL+"/events?id=1&limit=1", records[0].URL)
+	assert.Equal(t, http.StatusOK, records[0].StatusCode)
+	assert.Equal(t, "application/json", records[0].Header.Get("Content-Type"))
+	assert.Equal(t, body, string(records[0].Body))
+	assert.False(t, records[0].Timestamp.IsZero())
+	assert.Equal(t, http.StatusInternalServerError, records[1].StatusCode)
+	assert.Equal(t, "boom\n", string(records[1].Body))
+}
+
+func TestRawBodyNotRetainedByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GetEventsKeysetResponse{Events: []Event{mockEvent("1")}})
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	response, err := client.GetEventsByKeysetPage("", 10)
+	require.NoError(t, err)
+	assert.Nil(t, response.Raw)
+}
+
+func TestArchiverErrorFailsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Archiver: archiverFunc(func(record *ArchiveRecord) error {
+			return errors.New("disk full")
+		}),
+	})
+
+	response, err := client.GetEventsByPage(0, 10, true)
+	require.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "failed to archive response: disk full")
+}
+
+func TestArchiverErrorKeepsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Archiver: archiverFunc(func(record *ArchiveRecord) error {
+			return errors.New("disk full")
+		}),
+	})
+
+	_, err := client.GetEventsByPage(0, 10, true)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Contains(t, err.Error(), "failed to archive response: disk full")
+}
+
+func TestDirArchiver(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	archiver, err := NewDirArchiver(dir)
+	require.NoError(t, err)
+
+	record := &ArchiveRecord{
+		URL:        "https://gamma-api.polymarket.com/events?id=1",
+		Timestamp:  time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC),
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Cf-Ray": []string{"abc"}},
+		Body:       []byte(`[{"id":"1"}]`),
+	}
+	require.NoError(t, archiver.Archive(record))
+	require.NoError(t, archiver.Archive(record))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{
+		"20260701T120000.000000000Z-000001.body",
+		"20260701T120000.000000000Z-000001.json",
+		"20260701T120000.000000000Z-000002.body",
+		"20260701T120000.000000000Z-000002.json",
+	}, names)
+
+	body, err := os.ReadFile(filepath.Join(dir, names[0]))
+	require.NoError(t, err)
+	assert.Equal(t, record.Body, body)
+
+	var metadata ArchiveRecord
+	encoded, err := os.ReadFile(filepath.Join(dir, names[1]))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &metadata))
+	assert.Equal(t, record.URL, metadata.URL)
+	assert.Equal(t, "abc", metadata.Header.Get("CF-Ray"))
+	assert.Nil(t, metadata.Body)
+}
+
+func TestNDJSONArchiverRotates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "responses.ndjson")
+
+	archiver, err := NewNDJSONArchiver(path, 300)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, archiver.Archive(&ArchiveRecord{
+			URL:        "https://gamma-api.polymarket.com/events",
+			Timestamp:  time.Now(),
+			StatusCode: http.StatusOK,
+			Body:       []byte("\x00binary\xff and a long enough body to force rotation"),
+		}))
+	}
+	require.NoError(t, archiver.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	records, err := ReadNDJSONArchive(path)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []byte("\x00binary\xff and a long enough body to force rotation"), records[0].Body)
+
+	assert.Error(t, archiver.Archive(&ArchiveRecord{}))
+}
@@ -0,0 +1,166 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// getEventsByIDChunks dedupes ids, fetches them in bounded chunks with limited
+// parallelism and merges the results back into the order of ids
+func (c *Client) getEventsByIDChunks(ctx context.Context, ids []int) (*GetEventsResponse, error) {
+	unique, responses, err := fetchByIDChunks(ctx, c, ids, c.getEventsByIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, response := range responses {
+		events = append(events, response.Events...)
+	}
+	merged := &GetEventsResponse{}
+	merged.Events, merged.MissingIDs = orderByID(unique, events, func(e Event) string { return e.ID })
+	if len(responses) == 1 {
+		merged.Meta = responses[0].Meta
+		merged.Raw = responses[0].Raw
+	}
+
+	return merged, nil
+}
+
+// getMarketsByIDChunks is getEventsByIDChunks for markets
+func (c *Client) getMarketsByIDChunks(ctx context.Context, ids []int) (*GetMarketsResponse, error) {
+	unique, responses, err := fetchByIDChunks(ctx, c, ids, c.getMarketsByIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var markets []Market
+	for _, response := range responses {
+		markets = append(markets, response.Markets...)
+	}
+	merged := &GetMarketsResponse{}
+	merged.Markets, merged.MissingIDs = orderByID(unique, markets, func(m Market) string { return m.ID })
+	if len(responses) == 1 {
+		merged.Meta = responses[0].Meta
+		merged.Raw = responses[0].Raw
+	}
+
+	return merged, nil
+}
+
+// fetchByIDChunks dedupes ids and fetches them in chunks of c.maxIDsPerRequest, at
+// most c.idParallelism at a time. It returns the unique IDs and one response per
+// chunk, in order. The first failure cancels the remaining chunks.
+func fetchByIDChunks[R any](ctx context.Context, c *Client, ids []int, fetch func(ctx context.Context, chunk []int) (R, error)) ([]int, []R, error) {
+	unique := dedupeIDs(ids)
+
+	var chunks [][]int
+	for start := 0; start < len(unique); start += c.maxIDsPerRequest {
+		chunks = append(chunks, unique[start:min(start+c.maxIDsPerRequest, len(unique))])
+	}
+
+	responses := make([]R, len(chunks))
+	if len(chunks) == 1 {
+		response, err := fetch(ctx, chunks[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		responses[0] = response
+		return unique, responses, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		slots    = make(chan struct{}, c.idParallelism)
+	)
+
+	for i, chunk := range chunks {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, chunk []int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			response, err := fetch(ctx, chunk)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("failed to fetch ID chunk %d of %d: %w", i+1, len(chunks), err)
+					cancel()
+				})
+				return
+			}
+			responses[i] = response
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return unique, responses, nil
+}
+
+// orderByID orders items by ids, recording the IDs that were not returned. Items
+// whose ID was not requested are kept at the end.
+func orderByID[T any](ids []int, items []T, idOf func(T) string) ([]T, []int) {
+	requested := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		requested[id] = true
+	}
+
+	byID := map[int]T{}
+	var unrequested []T
+	for _, item := range items {
+		id, err := strconv.Atoi(idOf(item))
+		if err != nil || !requested[id] {
+			unrequested = append(unrequested, item)
+			continue
+		}
+		if _, seen := byID[id]; !seen {
+			byID[id] = item
+		}
+	}
+
+	ordered := make([]T, 0, len(byID)+len(unrequested))
+	var missing []int
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		ordered = append(ordered, item)
+	}
+	ordered = append(ordered, unrequested...)
+
+	return ordered, missing
+}
+
+func dedupeIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,150 @@
+package polymarket_gamma
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIDServer echoes back an event, or a market on /markets, for every requested id
+// except those in missing, in reverse order, tracking the peak number of concurrent requests
+func newIDServer(t *testing.T, hits, peak *atomic.Int32, missing map[string]bool) *httptest.Server {
+	var inFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		ids := r.URL.Query()["id"]
+		events := []Event{}
+		markets := []Market{}
+		for i := len(ids) - 1; i >= 0; i-- {
+			if !missing[ids[i]] {
+				events = append(events, mockEvent(ids[i]))
+				markets = append(markets, Market{ID: ids[i]})
+			}
+		}
+		if r.URL.Path == "/markets" {
+			json.NewEncoder(w).Encode(markets)
+			return
+		}
+		json.NewEncoder(w).Encode(events)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetEventsByIDsChunksLargeRequests(t *testing.T) {
+	var hits, peak atomic.Int32
+	server := newIDServer(t, &hits, &peak, map[string]bool{"4": true})
+
+	client := NewClient(&ClientConfig{
+		BaseURL:          server.URL,
+		MaxIDsPerRequest: 2,
+		IDParallelism:    2,
+	})
+
+	response, err := client.GetEventsByIDs([]int{7, 3, 4, 1, 3, 9, 2, 8, 5})
+	require.NoError(t, err)
+
+	// 8 unique ids in chunks of 2
+	assert.Equal(t, int32(4), hits.Load())
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+
+	var ids []string
+	for _, event := range response.Events {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"7", "3", "1", "9", "2", "8", "5"}, ids)
+	assert.Equal(t, []int{4}, response.MissingIDs)
+	assert.Nil(t, response.Meta, "split lookups have no single response")
+}
+
+func TestGetEventsByIDsSingleChunkKeepsMeta(t *testing.T) {
+	var hits, peak atomic.Int32
+	server := newIDServer(t, &hits, &peak, nil)
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, RetainRawBody: true})
+
+	response, err := client.GetEventsByIDs([]int{1, 2, 1})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+	require.Len(t, response.Events, 2)
+	assert.Equal(t, "1", response.Events[0].ID)
+	require.NotNil(t, response.Meta)
+	assert.Equal(t, http.StatusOK, response.Meta.StatusCode)
+	assert.NotEmpty(t, response.Raw)
+}
+
+func TestGetEventsByIDsChunkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "3" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]Event{mockEvent(r.URL.Query().Get("id"))})
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, MaxIDsPerRequest: 1})
+
+	_, err := client.GetEventsByIDs([]int{1, 2, 3, 4})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed to fetch ID chunk 3 of 4")
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestGetMarketsByIDsChunksConcurrently(t *testing.T) {
+	var hits, peak atomic.Int32
+	server := newIDServer(t, &hits, &peak, map[string]bool{"4": true})
+
+	client := NewClient(&ClientConfig{
+		BaseURL:          server.URL,
+		MaxIDsPerRequest: 2,
+		IDParallelism:    3,
+	})
+
+	response, err := client.GetMarketsByIDs([]int{7, 3, 4, 1, 3, 9, 2, 8, 5})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(4), hits.Load())
+	assert.Greater(t, peak.Load(), int32(1), "chunks should be fetched concurrently")
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+
+	var ids []string
+	for _, market := range response.Markets {
+		ids = append(ids, market.ID)
+	}
+	assert.Equal(t, []string{"7", "3", "1", "9", "2", "8", "5"}, ids)
+	assert.Equal(t, []int{4}, response.MissingIDs)
+	assert.Nil(t, response.Meta)
+}
+
+func TestGetByIDsWithNoIDsMakesNoRequest(t *testing.T) {
+	var hits, peak atomic.Int32
+	client := NewClient(&ClientConfig{BaseURL: newIDServer(t, &hits, &peak, nil).URL})
+
+	events, err := client.GetEventsByIDs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, events.Events)
+	assert.Empty(t, events.MissingIDs)
+
+	markets, err := client.GetMarketsByIDs([]int{})
+	require.NoError(t, err)
+	assert.Empty(t, markets.Markets)
+
+	assert.Zero(t, hits.Load())
+}
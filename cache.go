@@ -0,0 +1,352 @@
+package polymarket_gamma
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultCacheBytes = 64 << 20
+
+// CacheStatus says how the response cache handled a request
+type CacheStatus string
+
+const (
+	// CacheMiss means the response came from the network and was stored
+	CacheMiss CacheStatus = "miss"
+	// CacheHit means a fresh cached response was served without a request
+	CacheHit CacheStatus = "hit"
+	// CacheStale means an expired response was served while it is refreshed in the
+	// background
+	CacheStale CacheStatus = "stale"
+	// CacheRevalidated means the server confirmed the cached response with a 304
+	CacheRevalidated CacheStatus = "revalidated"
+)
+
+type CacheConfig struct {
+	// Store holds cached responses (default: an in-memory LRU capped at 64MB)
+	Store CacheStore
+	// TTL is how long a response is served without asking the server. With a zero
+	// TTL every call is revalidated, which is still cheap when the server sends
+	// ETag or Last-Modified.
+	TTL time.Duration
+	// EndpointTTLs overrides TTL per endpoint, e.g. {"/events/keyset": time.Minute}
+	EndpointTTLs map[string]time.Duration
+	// StaleWhileRevalidate serves an expired response for up to this long after its
+	// TTL while a fresh copy is fetched in the background
+	StaleWhileRevalidate time.Duration
+}
+
+// CacheEntry is a cached 200 response body with the headers needed to revalidate it
+type CacheEntry struct {
+	Body     []byte      `json:"body"`
+	Header   http.Header `json:"header"`
+	URL      string      `json:"url"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+func (e *CacheEntry) size() int64 {
+	size := int64(len(e.Body) + len(e.URL))
+	for key, values := range e.Header {
+		size += int64(len(key))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	return size
+}
+
+// CacheStore is a cache backend. Entries must be treated as immutable once stored.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+type responseCache struct {
+	config *CacheConfig
+
+	mu           sync.Mutex
+	revalidating map[string]bool
+}
+
+func newResponseCache(config *CacheConfig) *responseCache {
+	if config == nil {
+		return nil
+	}
+
+	normalized := *config
+	if normalized.Store == nil {
+		normalized.Store = NewMemoryCache(defaultCacheBytes)
+	}
+
+	return &responseCache{
+		config:       &normalized,
+		revalidating: map[string]bool{},
+	}
+}
+
+func (r *responseCache) ttl(endpoint string) time.Duration {
+	if ttl, ok := r.config.EndpointTTLs[endpoint]; ok {
+		return ttl
+	}
+	return r.config.TTL
+}
+
+// fetchCached serves info from the cache when possible, falling back to a
+// (conditional) network request
+func (c *Client) fetchCached(ctx context.Context, info *RequestInfo) ([]byte, error) {
+	key := cacheKey(info.Endpoint, info.Query)
+	entry, ok := c.cache.config.Store.Get(key)
+	if !ok {
+		entry = nil
+	}
+
+	if entry != nil {
+		age := time.Since(entry.StoredAt)
+		ttl := c.cache.ttl(info.Endpoint)
+
+		switch {
+		case age < ttl:
+			info.CacheStatus = CacheHit
+			return serveCached(info, entry), nil
+		case age < ttl+c.cache.config.StaleWhileRevalidate:
+			info.CacheStatus = CacheStale
+			c.revalidateInBackground(key, info, entry)
+			return serveCached(info, entry), nil
+		}
+	}
+
+	return c.refresh(ctx, key, info, entry)
+}
+
+// refresh fetches info from the network, revalidating entry if there is one, and
+// updates the cache
+func (c *Client) refresh(ctx context.Context, key string, info *RequestInfo, entry *CacheEntry) ([]byte, error) {
+	body, err := c.fetchNetwork(ctx, info, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.StatusCode == http.StatusNotModified {
+		info.CacheStatus = CacheRevalidated
+		info.BytesDecoded = int64(len(entry.Body))
+
+		refreshed := *entry
+		refreshed.StoredAt = time.Now()
+		c.cache.config.Store.Set(key, &refreshed)
+		return entry.Body, nil
+	}
+
+	info.CacheStatus = CacheMiss
+	c.cache.config.Store.Set(key, &CacheEntry{
+		Body:     body,
+		Header:   info.Header.Clone(),
+		URL:      info.FinalURL,
+		StoredAt: time.Now(),
+	})
+	return body, nil
+}
+
+// revalidateInBackground refreshes a stale entry, at most once at a time per key.
+// The refresh is detached from the caller's context so it outlives the call.
+func (c *Client) revalidateInBackground(key string, info *RequestInfo, entry *CacheEntry) {
+	c.cache.mu.Lock()
+	if c.cache.revalidating[key] {
+		c.cache.mu.Unlock()
+		return
+	}
+	c.cache.revalidating[key] = true
+	c.cache.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.cache.mu.Lock()
+			delete(c.cache.revalidating, key)
+			c.cache.mu.Unlock()
+		}()
+
+		background := c.newRequestInfo(info.Endpoint, info.Query)
+		ctx := c.startRequest(context.Background(), background)
+		_, err := c.refresh(ctx, key, background, entry)
+		c.observe(ctx, background, err)
+	}()
+}
+
+// uncache drops the cached response for info after its body failed to decode or
+// validate. The body is cached before it is decoded, so without this every call
+// within the TTL would fail from the cache instead of asking the server again.
+func (c *Client) uncache(info *RequestInfo) {
+	if c.cache != nil {
+		c.cache.config.Store.Delete(cacheKey(info.Endpoint, info.Query))
+	}
+}
+
+func serveCached(info *RequestInfo, entry *CacheEntry) []byte {
+	info.StatusCode = http.StatusOK
+	// Cloned so callers can't modify the stored entry through Meta.Header
+	info.Header = entry.Header.Clone()
+	info.FinalURL = entry.URL
+	info.BytesDecoded = int64(len(entry.Body))
+	return entry.Body
+}
+
+// cacheKey normalises a request so that parameter order does not matter
+func cacheKey(endpoint string, queryParams url.Values) string {
+	normalized := url.Values{}
+	for key, values := range queryParams {
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		normalized[key] = sorted
+	}
+	return endpoint + "?" + normalized.Encode()
+}
+
+// MemoryCache is an in-memory LRU CacheStore bounded by the total size of its entries
+type MemoryCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+	size  int64
+}
+
+// NewMemoryCache returns an LRU cache holding at most maxBytes of responses
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(element)
+	return element.Value.(*memoryCacheItem).entry, true
+}
+
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(key)
+
+	item := &memoryCacheItem{key: key, entry: entry, size: entry.size() + int64(len(key))}
+	if item.size > m.maxBytes {
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(item)
+	m.size += item.size
+
+	for m.size > m.maxBytes {
+		m.remove(m.order.Back().Value.(*memoryCacheItem).key)
+	}
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(key)
+}
+
+// Size returns the total size of the cached entries in bytes
+func (m *MemoryCache) Size() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.size
+}
+
+func (m *MemoryCache) remove(key string) {
+	element, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	m.order.Remove(element)
+	delete(m.entries, key)
+	m.size -= element.Value.(*memoryCacheItem).size
+}
+
+// DirCache is a CacheStore keeping one JSON file per entry in a directory, so the
+// cache survives restarts. Write failures are ignored, as with any cache miss.
+type DirCache struct {
+	dir string
+}
+
+// NewDirCache creates dir if needed and returns a cache stored in it
+func NewDirCache(dir string) (*DirCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirCache{dir: dir}, nil
+}
+
+func (d *DirCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (d *DirCache) Set(key string, entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (d *DirCache) Delete(key string) {
+	os.Remove(d.path(key))
+}
+
+func (d *DirCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,280 @@
+package polymarket_gamma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVersionedServer serves a single event whose title changes with version, and
+// answers conditional requests with 304 while the version is unchanged
+func newVersionedServer(t *testing.T, version *atomic.Int32, hits *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		etag := fmt.Sprintf(`"v%d"`, version.Load())
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		event := mockEvent("1")
+		event.Title = fmt.Sprintf("version %d", version.Load())
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/events/keyset" {
+			json.NewEncoder(w).Encode(GetEventsKeysetResponse{Events: []Event{event}})
+			return
+		}
+		json.NewEncoder(w).Encode([]Event{event})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCacheHitWithinTTL(t *testing.T) {
+	var version, hits atomic.Int32
+	server := newVersionedServer(t, &version, &hits)
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Cache:   &CacheConfig{TTL: time.Hour},
+	})
+
+	first, err := client.GetEventsByIDs([]int{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, first.Meta.CacheStatus)
+
+	// Parameter order does not change the cache key
+	second, err := client.GetEventsByIDs([]int{2, 1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheHit, second.Meta.CacheStatus)
+	assert.Equal(t, first.Events, second.Events)
+	assert.Equal(t, int32(1), hits.Load())
+
+	// Callers get their own decoded copies
+	second.Events[0].Title = "mutated"
+	third, err := client.GetEventsByIDs([]int{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, "version 0", third.Events[0].Title)
+}
+
+func TestCacheEndpointTTLs(t *testing.T) {
+	var version, hits atomic.Int32
+	server := newVersionedServer(t, &version, &hits)
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Cache: &CacheConfig{
+			TTL:          time.Hour,
+			EndpointTTLs: map[string]time.Duration{"/events/keyset": 0},
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetEventsByKeysetPage("", 10)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), hits.Load(), "keyset pages are always revalidated")
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	var version, hits atomic.Int32
+	server := newVersionedServer(t, &version, &hits)
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Cache:   &CacheConfig{},
+	})
+
+	_, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+
+	revalidated, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheRevalidated, revalidated.Meta.CacheStatus)
+	assert.Equal(t, http.StatusNotModified, revalidated.Meta.StatusCode)
+	assert.Equal(t, "version 0", revalidated.Events[0].Title)
+
+	version.Store(1)
+	changed, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, changed.Meta.CacheStatus)
+	assert.Equal(t, "version 1", changed.Events[0].Title)
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestCacheRevalidatesWithLastModified(t *testing.T) {
+	lastModified := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		json.NewEncoder(w).Encode([]Event{mockEvent("1")})
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Cache: &CacheConfig{}})
+
+	_, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	response, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheRevalidated, response.Meta.CacheStatus)
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	var version, hits atomic.Int32
+	server := newVersionedServer(t, &version, &hits)
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Cache: &CacheConfig{
+			TTL:                  10 * time.Millisecond,
+			StaleWhileRevalidate: time.Hour,
+		},
+	})
+
+	_, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+
+	version.Store(1)
+	time.Sleep(20 * time.Millisecond)
+
+	stale, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheStale, stale.Meta.CacheStatus)
+	assert.Equal(t, "version 0", stale.Events[0].Title)
+
+	// The background refresh replaces the stale entry
+	assert.Eventually(t, func() bool {
+		fresh, err := client.GetEventsByIDs([]int{1})
+		return err == nil && fresh.Events[0].Title == "version 1"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]Event{mockEvent("1")})
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Cache: &CacheConfig{TTL: time.Hour}})
+
+	_, err := client.GetEventsByIDs([]int{1})
+	require.Error(t, err)
+	response, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, response.Meta.CacheStatus)
+}
+
+func TestCacheDropsInvalidResponses(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch hits.Add(1) {
+		case 1:
+			w.Write([]byte(`not json`))
+		case 2:
+			w.Write([]byte(`[{"slug": "no-id"}]`))
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			json.NewEncoder(w).Encode([]Event{mockEvent("1")})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Cache: &CacheConfig{TTL: time.Hour}})
+
+	_, err := client.GetEventsByIDs([]int{1})
+	require.ErrorContains(t, err, "failed to parse response")
+	_, err = client.GetEventsByIDs([]int{1})
+	require.ErrorContains(t, err, "validation failed")
+	response, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheMiss, response.Meta.CacheStatus)
+	assert.Equal(t, int32(3), hits.Load())
+
+	// The cached entry's headers can't be changed through a response
+	response.Meta.Header.Set("ETag", "mutated")
+	cached, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheHit, cached.Meta.CacheStatus)
+	assert.Equal(t, `"v1"`, cached.Meta.Header.Get("ETag"))
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	entry := func(n int) *CacheEntry {
+		return &CacheEntry{Body: make([]byte, n)}
+	}
+
+	cache := NewMemoryCache(250)
+	cache.Set("a", entry(100))
+	cache.Set("b", entry(100))
+
+	// Touch "a" so "b" is the least recently used
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	cache.Set("c", entry(100))
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, int64(202), cache.Size())
+
+	// Entries larger than the cache are never stored
+	cache.Set("huge", entry(1000))
+	_, ok = cache.Get("huge")
+	assert.False(t, ok)
+
+	cache.Delete("a")
+	assert.Equal(t, int64(101), cache.Size())
+}
+
+func TestDirCachePersists(t *testing.T) {
+	var version, hits atomic.Int32
+	server := newVersionedServer(t, &version, &hits)
+	dir := t.TempDir()
+
+	newClient := func() *Client {
+		store, err := NewDirCache(dir)
+		require.NoError(t, err)
+		return NewClient(&ClientConfig{
+			BaseURL: server.URL,
+			Cache:   &CacheConfig{Store: store, TTL: time.Hour},
+		})
+	}
+
+	_, err := newClient().GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+
+	response, err := newClient().GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Equal(t, CacheHit, response.Meta.CacheStatus)
+	assert.Equal(t, "version 0", response.Events[0].Title)
+	assert.Equal(t, int32(1), hits.Load())
+
+	store, err := NewDirCache(dir)
+	require.NoError(t, err)
+	key := cacheKey("/events", map[string][]string{"id": {"1"}, "limit": {"1"}})
+	_, ok := store.Get(key)
+	require.True(t, ok)
+	store.Delete(key)
+	_, ok = store.Get(key)
+	assert.False(t, ok)
+}
@@ -37,7 +37,10 @@
 package polymarket_gamma
 
 import (
+	"bytes"
 	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -53,6 +56,9 @@ import (
 const (
 	defaultBaseURL = "https://gamma-api.polymarket.com"
 	defaultTimeout = 30 * time.Second
+
+	defaultMaxIDsPerRequest = 100
+	defaultIDParallelism    = 4
 )
 
 type ClientConfig struct {
@@ -62,6 +68,22 @@ type ClientConfig struct {
 	Transport http.RoundTripper
 	// Custom HTTP client (optional)
 	HTTPClient *http.Client
+	// Observer is notified after every request to the API (optional)
+	Observer Observer
+	// RetainRawBody keeps the decompressed response body on each response's Raw field
+	RetainRawBody bool
+	// Archiver receives every raw response for auditing or later replay (optional)
+	Archiver Archiver
+	// Cache enables response caching (optional)
+	Cache *CacheConfig
+	// CoalesceRequests makes identical concurrent requests share a single round trip
+	CoalesceRequests bool
+	// MaxIDsPerRequest bounds how many IDs GetEventsByIDs sends per request
+	// (default 100, the API's row cap)
+	MaxIDsPerRequest int
+	// IDParallelism bounds how many GetEventsByIDs chunks are fetched at once
+	// (default 4)
+	IDParallelism int
 }
 
 // Polymarket Gamma API client
@@ -69,6 +91,16 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	validator  *validator.Validate
+	observer   Observer
+
+	retainRawBody bool
+	archiver      Archiver
+
+	cache   *responseCache
+	flights *flightGroup
+
+	maxIDsPerRequest int
+	idParallelism    int
 }
 
 func NewClient(config *ClientConfig) *Client {
@@ -99,37 +131,84 @@ func NewClient(config *ClientConfig) *Client {
 		}
 	}
 
-	return &Client{
+	client := &Client{
 		baseURL:    baseURL,
 		httpClient: httpClient,
 		validator:  validator.New(),
+		observer:   config.Observer,
+
+		retainRawBody: config.RetainRawBody,
+		archiver:      config.Archiver,
+
+		cache: newResponseCache(config.Cache),
+
+		maxIDsPerRequest: config.MaxIDsPerRequest,
+		idParallelism:    config.IDParallelism,
+	}
+	if client.maxIDsPerRequest <= 0 {
+		client.maxIDsPerRequest = defaultMaxIDsPerRequest
+	}
+	if client.idParallelism <= 0 {
+		client.idParallelism = defaultIDParallelism
 	}
+	if config.CoalesceRequests {
+		client.flights = newFlightGroup()
+	}
+
+	return client
 }
 
-// GetEventsByIDs fetches events by their IDs from the Polymarket Gamma API
+// GetEventsByIDs fetches events by their IDs from the Polymarket Gamma API.
+//
+// Repeated IDs are fetched once. Large ID lists are split into chunks of
+// ClientConfig.MaxIDsPerRequest, which are fetched concurrently; events are returned
+// in the order of ids, and IDs the API did not return are listed in MissingIDs. An
+// empty ids returns no events without making a request.
 func (c *Client) GetEventsByIDs(ids []int) (*GetEventsResponse, error) {
+	return c.GetEventsByIDsContext(context.Background(), ids)
+}
+
+// GetEventsByIDsContext is GetEventsByIDs with a caller-supplied context
+func (c *Client) GetEventsByIDsContext(ctx context.Context, ids []int) (*GetEventsResponse, error) {
+	return c.getEventsByIDChunks(ctx, ids)
+}
+
+// getEventsByIDs fetches a single chunk of IDs in one request
+func (c *Client) getEventsByIDs(ctx context.Context, ids []int) (*GetEventsResponse, error) {
 	queryParams := url.Values{}
 
 	// Add multiple id parameters (API expects integers)
 	for _, id := range ids {
 		queryParams.Add("id", strconv.Itoa(id))
 	}
+	// Without a limit the API returns its default of 20 rows, whatever the IDs
+	queryParams.Set("limit", strconv.Itoa(len(ids)))
 
-	return c.getEvents(queryParams)
+	return c.getEvents(ctx, queryParams)
 }
 
 // GetEventsByPage fetches events with pagination from the Polymarket Gamma API
 func (c *Client) GetEventsByPage(offset, limit int, ascending bool) (*GetEventsResponse, error) {
+	return c.GetEventsByPageContext(context.Background(), offset, limit, ascending)
+}
+
+// GetEventsByPageContext is GetEventsByPage with a caller-supplied context
+func (c *Client) GetEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error) {
 	queryParams := url.Values{}
 	queryParams.Set("offset", strconv.Itoa(offset))
 	queryParams.Set("limit", strconv.Itoa(limit))
 	queryParams.Set("ascending", strconv.FormatBool(ascending))
 	queryParams.Set("order", "id")
 
-	return c.getEvents(queryParams)
+	return c.getEvents(ctx, queryParams)
 }
 
 func (c *Client) GetActiveEventsByPage(offset, limit int, ascending bool) (*GetEventsResponse, error) {
+	return c.GetActiveEventsByPageContext(context.Background(), offset, limit, ascending)
+}
+
+// GetActiveEventsByPageContext is GetActiveEventsByPage with a caller-supplied context
+func (c *Client) GetActiveEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error) {
 	queryParams := url.Values{}
 	queryParams.Set("offset", strconv.Itoa(offset))
 	queryParams.Set("limit", strconv.Itoa(limit))
@@ -137,7 +216,24 @@ func (c *Client) GetActiveEventsByPage(offset, limit int, ascending bool) (*GetE
 	queryParams.Set("order", "id")
 	queryParams.Set("closed", "false") // polymarket doesn't seem to use the `active` column
 
-	return c.getEvents(queryParams)
+	return c.getEvents(ctx, queryParams)
+}
+
+// GetEventsByUpdatedAtPage fetches events ordered by their updatedAt timestamp, for
+// picking up recent changes. It is offset paginated, so the offset cap applies.
+func (c *Client) GetEventsByUpdatedAtPage(offset, limit int, ascending bool) (*GetEventsResponse, error) {
+	return c.GetEventsByUpdatedAtPageContext(context.Background(), offset, limit, ascending)
+}
+
+// GetEventsByUpdatedAtPageContext is GetEventsByUpdatedAtPage with a caller-supplied context
+func (c *Client) GetEventsByUpdatedAtPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error) {
+	queryParams := url.Values{}
+	queryParams.Set("offset", strconv.Itoa(offset))
+	queryParams.Set("limit", strconv.Itoa(limit))
+	queryParams.Set("ascending", strconv.FormatBool(ascending))
+	queryParams.Set("order", "updatedAt")
+
+	return c.getEvents(ctx, queryParams)
 }
 
 // GetEventsByKeysetPage fetches a single page of events from the Polymarket Gamma API
@@ -153,18 +249,29 @@ func (c *Client) GetActiveEventsByPage(offset, limit int, ascending bool) (*GetE
 // truncated to 100 rows), so keyset pagination is the only way to enumerate the
 // full event set. Events are returned in ascending id order.
 func (c *Client) GetEventsByKeysetPage(afterCursor string, limit int) (*GetEventsKeysetResponse, error) {
+	return c.GetEventsByKeysetPageContext(context.Background(), afterCursor, limit)
+}
+
+// GetEventsByKeysetPageContext is GetEventsByKeysetPage with a caller-supplied context
+func (c *Client) GetEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*GetEventsKeysetResponse, error) {
 	queryParams := url.Values{}
 	queryParams.Set("limit", strconv.Itoa(limit))
 	if afterCursor != "" {
 		queryParams.Set("after_cursor", afterCursor)
 	}
 
-	return c.getEventsKeyset(queryParams)
+	return c.getEventsKeyset(ctx, queryParams)
 }
 
 // GetActiveEventsByKeysetPage is GetEventsByKeysetPage restricted to events that have
 // not closed yet.
 func (c *Client) GetActiveEventsByKeysetPage(afterCursor string, limit int) (*GetEventsKeysetResponse, error) {
+	return c.GetActiveEventsByKeysetPageContext(context.Background(), afterCursor, limit)
+}
+
+// GetActiveEventsByKeysetPageContext is GetActiveEventsByKeysetPage with a
+// caller-supplied context
+func (c *Client) GetActiveEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*GetEventsKeysetResponse, error) {
 	queryParams := url.Values{}
 	queryParams.Set("limit", strconv.Itoa(limit))
 	if afterCursor != "" {
@@ -172,152 +279,337 @@ func (c *Client) GetActiveEventsByKeysetPage(afterCursor string, limit int) (*Ge
 	}
 	queryParams.Set("closed", "false") // polymarket doesn't seem to use the `active` column
 
-	return c.getEventsKeyset(queryParams)
+	return c.getEventsKeyset(ctx, queryParams)
 }
 
 // getEvents is the private implementation that fetches events from the Polymarket Gamma API
-func (c *Client) getEvents(queryParams url.Values) (*GetEventsResponse, error) {
+func (c *Client) getEvents(ctx context.Context, queryParams url.Values) (response *GetEventsResponse, err error) {
+	info := c.newRequestInfo("/events", queryParams)
+	ctx = c.startRequest(ctx, info)
+	defer func() { c.observe(ctx, info, err) }()
 
-	// Build URL
-	apiURL := fmt.Sprintf("%s/events", c.baseURL)
-	if len(queryParams) > 0 {
-		apiURL = fmt.Sprintf("%s?%s", apiURL, queryParams.Encode())
+	body, err := c.fetch(ctx, info)
+	if err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+	var events []Event
+	decodeStart := time.Now()
+	err = sonic.Unmarshal(body, &events)
+	info.DecodeDuration = time.Since(decodeStart)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		info.ErrorClass = ErrorClassDecode
+		c.uncache(info)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	info.Events = len(events)
 
-	// Accept gzip encoding to reduce bandwidth
-	req.Header.Set("Accept-Encoding", "gzip")
+	if err := c.validateEvents(info, events); err != nil {
+		c.uncache(info)
+		return nil, err
+	}
 
-	resp, err := c.httpClient.Do(req)
+	response = &GetEventsResponse{
+		Events: events,
+		Meta:   info.meta(),
+		Raw:    c.rawBody(body),
+	}
+
+	return response, nil
+}
+
+// getEventsKeyset is the private implementation that fetches a single keyset page
+// from the Polymarket Gamma API's /events/keyset endpoint
+func (c *Client) getEventsKeyset(ctx context.Context, queryParams url.Values) (response *GetEventsKeysetResponse, err error) {
+	info := c.newRequestInfo("/events/keyset", queryParams)
+	ctx = c.startRequest(ctx, info)
+	defer func() { c.observe(ctx, info, err) }()
+
+	body, err := c.fetch(ctx, info)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to fetch events: %d %s - %s", resp.StatusCode, resp.Status, string(body))
+	response = &GetEventsKeysetResponse{}
+	decodeStart := time.Now()
+	err = sonic.Unmarshal(body, response)
+	info.DecodeDuration = time.Since(decodeStart)
+	if err != nil {
+		info.ErrorClass = ErrorClassDecode
+		c.uncache(info)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	info.Events = len(response.Events)
 
-	// Handle gzip decompression if needed
-	var reader io.Reader = resp.Body
-	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
-		gzipReader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-		}
-		defer gzipReader.Close()
-		reader = gzipReader
+	if err := c.validateEvents(info, response.Events); err != nil {
+		c.uncache(info)
+		return nil, err
 	}
+	response.Meta = info.meta()
+	response.Raw = c.rawBody(body)
+
+	return response, nil
+}
+
+// getJSON fetches endpoint and decodes the body into a T, observing the request like
+// getEvents. validate, if given, checks the decoded value. The raw body is returned
+// for ClientConfig.RetainRawBody.
+func getJSON[T any](ctx context.Context, c *Client, endpoint string, queryParams url.Values, validate func(info *RequestInfo, value *T) error) (value *T, info *RequestInfo, body []byte, err error) {
+	info = c.newRequestInfo(endpoint, queryParams)
+	ctx = c.startRequest(ctx, info)
+	defer func() { c.observe(ctx, info, err) }()
 
-	body, err := io.ReadAll(reader)
+	body, err = c.fetch(ctx, info)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, info, nil, err
 	}
 
-	var events []Event
-	if err := sonic.Unmarshal(body, &events); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	value = new(T)
+	decodeStart := time.Now()
+	err = sonic.Unmarshal(body, value)
+	info.DecodeDuration = time.Since(decodeStart)
+	if err != nil {
+		info.ErrorClass = ErrorClassDecode
+		c.uncache(info)
+		return nil, info, nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	for i, event := range events {
-		// Validate event (skipMissingProperties and whitelist:false equivalent)
-		if err := c.validator.Struct(event); err != nil {
-			if validationErrs, ok := err.(validator.ValidationErrors); ok {
-				return nil, fmt.Errorf("validation failed for event %d: %v", i, validationErrs)
-			}
-			return nil, fmt.Errorf("validation failed for event %d: %w", i, err)
-		}
-
-		// Validate markets
-		for j, market := range event.Markets {
-			if err := c.validator.Struct(market); err != nil {
-				if validationErrs, ok := err.(validator.ValidationErrors); ok {
-					return nil, fmt.Errorf("validation failed for market %d in event %d: %v", j, i, validationErrs)
-				}
-				return nil, fmt.Errorf("validation failed for market %d in event %d: %w", j, i, err)
-			}
+	if validate != nil {
+		if err = validate(info, value); err != nil {
+			c.uncache(info)
+			return nil, info, nil, err
 		}
 	}
 
-	return &GetEventsResponse{
-		Events: events,
-	}, nil
+	return value, info, body, nil
 }
 
-// getEventsKeyset is the private implementation that fetches a single keyset page
-// from the Polymarket Gamma API's /events/keyset endpoint
-func (c *Client) getEventsKeyset(queryParams url.Values) (*GetEventsKeysetResponse, error) {
+// rawBody returns a copy of body if ClientConfig.RetainRawBody is set, and nil
+// otherwise. The copy is needed because decoded strings may share memory with body.
+func (c *Client) rawBody(body []byte) []byte {
+	if !c.retainRawBody {
+		return nil
+	}
+	return bytes.Clone(body)
+}
+
+// fetch returns the decompressed response body for info.Endpoint, sharing the
+// request with identical concurrent calls if coalescing is enabled
+func (c *Client) fetch(ctx context.Context, info *RequestInfo) ([]byte, error) {
+	if c.flights != nil {
+		return c.fetchCoalesced(ctx, info)
+	}
+	return c.fetchOnce(ctx, info)
+}
 
-	// Build URL
-	apiURL := fmt.Sprintf("%s/events/keyset", c.baseURL)
-	if len(queryParams) > 0 {
-		apiURL = fmt.Sprintf("%s?%s", apiURL, queryParams.Encode())
+// fetchOnce returns the decompressed response body for info.Endpoint, from the
+// cache if one is configured and otherwise from the network
+func (c *Client) fetchOnce(ctx context.Context, info *RequestInfo) ([]byte, error) {
+	if c.cache != nil {
+		return c.fetchCached(ctx, info)
 	}
+	return c.fetchNetwork(ctx, info, nil)
+}
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+// fetchNetwork issues a GET request for info.Endpoint and returns the decompressed
+// response body. Transfer statistics are recorded on info as they become known.
+//
+// If cached is given, the request is made conditional on its validators; a 304
+// response returns a nil body with info.StatusCode set to http.StatusNotModified.
+func (c *Client) fetchNetwork(ctx context.Context, info *RequestInfo, cached *CacheEntry) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", info.URL, nil)
 	if err != nil {
+		info.ErrorClass = ErrorClassTransport
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Accept gzip encoding to reduce bandwidth
 	req.Header.Set("Accept-Encoding", "gzip")
+	if injector, ok := c.observer.(HeaderInjector); ok {
+		injector.InjectHeader(ctx, req.Header)
+	}
+	if cached != nil {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
 
+	sendStart := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		info.ErrorClass = ErrorClassTransport
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
+	info.StatusCode = resp.StatusCode
+	info.Header = resp.Header
+	info.FinalURL = resp.Request.URL.String()
+
+	counter := &countingReader{r: resp.Body}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		io.Copy(io.Discard, counter)
+		info.BytesReceived = counter.n
+		info.Latency = time.Since(sendStart)
+		return nil, nil
+	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to fetch events: %d %s - %s", resp.StatusCode, resp.Status, string(body))
+		body, _ := io.ReadAll(counter)
+		// Error bodies are kept as they arrived if they don't decompress
+		if decoded, err := decodeBody(resp, bytes.NewReader(body)); err == nil {
+			body = decoded
+		}
+		info.BytesReceived = counter.n
+		info.BytesDecoded = int64(len(body))
+		info.Latency = time.Since(sendStart)
+		apiErr := &APIError{Endpoint: info.Endpoint, StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
+		// A failed archive is reported alongside the API error, which stays
+		// reachable through errors.As
+		archiveErr := c.archive(info, body)
+		info.ErrorClass = ErrorClassHTTP
+		if archiveErr != nil {
+			return nil, errors.Join(apiErr, archiveErr)
+		}
+		return nil, apiErr
+	}
+
+	body, err := decodeBody(resp, counter)
+	info.BytesReceived = counter.n
+	info.BytesDecoded = int64(len(body))
+	info.Latency = time.Since(sendStart)
+	if err != nil {
+		info.ErrorClass = ErrorClassDecode
+		return nil, err
 	}
 
-	// Handle gzip decompression if needed
-	var reader io.Reader = resp.Body
+	if err := c.archive(info, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// decodeBody reads the response body from r, decompressing it if the server gzipped
+// it
+func decodeBody(resp *http.Response, r io.Reader) ([]byte, error) {
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
-		gzipReader, err := gzip.NewReader(resp.Body)
+		gzipReader, err := gzip.NewReader(r)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer gzipReader.Close()
-		reader = gzipReader
+		r = gzipReader
 	}
 
-	body, err := io.ReadAll(reader)
+	body, err := io.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	return body, nil
+}
 
-	var response GetEventsKeysetResponse
-	if err := sonic.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// archive hands a raw response body to the configured Archiver, if any
+func (c *Client) archive(info *RequestInfo, body []byte) error {
+	if c.archiver == nil {
+		return nil
 	}
 
-	for i, event := range response.Events {
+	err := c.archiver.Archive(&ArchiveRecord{
+		URL:        info.FinalURL,
+		Timestamp:  info.Start,
+		StatusCode: info.StatusCode,
+		Header:     info.Header,
+		Body:       body,
+	})
+	if err != nil {
+		info.ErrorClass = ErrorClassArchive
+		return fmt.Errorf("failed to archive response: %w", err)
+	}
+
+	return nil
+}
+
+// validateEvents validates every event and each of its markets, recording the time
+// spent on info.
+func (c *Client) validateEvents(info *RequestInfo, events []Event) error {
+	validationStart := time.Now()
+	defer func() { info.ValidationDuration = time.Since(validationStart) }()
+
+	for i, event := range events {
 		// Validate event (skipMissingProperties and whitelist:false equivalent)
 		if err := c.validator.Struct(event); err != nil {
+			info.ErrorClass = ErrorClassValidation
 			if validationErrs, ok := err.(validator.ValidationErrors); ok {
-				return nil, fmt.Errorf("validation failed for event %d: %v", i, validationErrs)
+				return fmt.Errorf("validation failed for event %d: %v", i, validationErrs)
 			}
-			return nil, fmt.Errorf("validation failed for event %d: %w", i, err)
+			return fmt.Errorf("validation failed for event %d: %w", i, err)
 		}
 
 		// Validate markets
 		for j, market := range event.Markets {
 			if err := c.validator.Struct(market); err != nil {
+				info.ErrorClass = ErrorClassValidation
 				if validationErrs, ok := err.(validator.ValidationErrors); ok {
-					return nil, fmt.Errorf("validation failed for market %d in event %d: %v", j, i, validationErrs)
+					return fmt.Errorf("validation failed for market %d in event %d: %v", j, i, validationErrs)
 				}
-				return nil, fmt.Errorf("validation failed for market %d in event %d: %w", j, i, err)
+				return fmt.Errorf("validation failed for market %d in event %d: %w", j, i, err)
 			}
 		}
 	}
 
-	return &response, nil
+	return nil
+}
+
+// validateMarkets validates every market, recording the time spent on info
+func (c *Client) validateMarkets(info *RequestInfo, markets []Market) error {
+	validationStart := time.Now()
+	defer func() { info.ValidationDuration = time.Since(validationStart) }()
+
+	for i, market := range markets {
+		if err := c.validator.Struct(market); err != nil {
+			info.ErrorClass = ErrorClassValidation
+			if validationErrs, ok := err.(validator.ValidationErrors); ok {
+				return fmt.Errorf("validation failed for market %d: %v", i, validationErrs)
+			}
+			return fmt.Errorf("validation failed for market %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// startRequest tells the configured Observer that a request is starting, if it wants
+// to know, and returns the context to make the request with
+func (c *Client) startRequest(ctx context.Context, info *RequestInfo) context.Context {
+	if starter, ok := c.observer.(RequestStarter); ok {
+		return starter.StartRequest(ctx, info)
+	}
+	return ctx
+}
+
+// observe reports a completed request to the configured Observer, if any
+func (c *Client) observe(ctx context.Context, info *RequestInfo, err error) {
+	if c.observer == nil {
+		return
+	}
+	info.Duration = time.Since(info.Start)
+	info.Err = err
+	c.observer.ObserveRequest(ctx, info)
+}
+
+// countingReader counts the bytes read through it, i.e. the bytes received on the
+// wire before any decompression
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
 }
@@ -1,16 +1,42 @@
 package polymarket_gamma
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/CalderWhite/polymarket-gamma-go/recorder"
 	. "github.com/onsi/gomega"
 )
 
+// newIntegrationClient returns a client whose traffic is replayed from a cassette at
+// testdata/cassettes/<test name>.json. The test is skipped if the cassette has not been
+// recorded. Run with GAMMA_CASSETTE=record to record or refresh it against the live
+// API, or GAMMA_CASSETTE=passthrough to skip the cassette.
+func newIntegrationClient(t *testing.T) *Client {
+	path := filepath.Join("testdata", "cassettes", t.Name()+".json")
+
+	transport, err := recorder.New(&recorder.Config{
+		Path: path,
+		Mode: recorder.ModeFromEnv("GAMMA_CASSETTE"),
+	})
+	if errors.Is(err, recorder.ErrMissingCassette) {
+		t.Skipf("%v, set GAMMA_CASSETTE=record to record it", err)
+	}
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+
+	return NewClient(&ClientConfig{
+		Transport: transport,
+	})
+}
+
 // TestGetRealEventsByPagination tests fetching events via pagination from the real API
 func TestGetRealEventsByPagination(t *testing.T) {
 	g := NewWithT(t)
-	client := NewClient(nil)
+	client := newIntegrationClient(t)
 
 	response, err := client.GetEventsByPage(0, 10, true)
 
@@ -22,7 +48,7 @@ func TestGetRealEventsByPagination(t *testing.T) {
 // TestGetExistingEvents tests fetching events 2890 and 2891 with full field validation
 func TestGetExistingEvents(t *testing.T) {
 	g := NewWithT(t)
-	client := NewClient(nil)
+	client := newIntegrationClient(t)
 
 	response, err := client.GetEventsByIDs([]int{2890, 2891})
 
@@ -120,7 +146,7 @@ func TestGetExistingEvents(t *testing.T) {
 // verifying that the cursor advances across pages
 func TestGetRealEventsByKeysetPagination(t *testing.T) {
 	g := NewWithT(t)
-	client := NewClient(nil)
+	client := newIntegrationClient(t)
 
 	page1, err := client.GetEventsByKeysetPage("", 100)
 
@@ -1,6 +1,8 @@
 package polymarket_gamma
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -362,8 +364,7 @@ func TestGetEventsAPIError(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Nil(t, response)
-	assert.Contains(t, err.Error(), "failed to fetch events")
-	assert.Contains(t, err.Error(), "500")
+	assert.Contains(t, err.Error(), "failed to fetch /events: 500")
 }
 
 func TestGetEventsValidationError(t *testing.T) {
@@ -727,3 +728,129 @@ func TestGetActiveEventsByKeysetPage(t *testing.T) {
 	assert.Equal(t, "10", response.Events[0].ID)
 	assert.Equal(t, "", response.NextCursor)
 }
+
+func TestObserverReceivesRequestInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") == "2" {
+			w.Write([]byte(`[{"slug": "missing-id"}]`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode([]Event{mockEvent("1")})
+	}))
+	defer server.Close()
+
+	var infos []*RequestInfo
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Observer: ObserverFunc(func(ctx context.Context, info *RequestInfo) {
+			infos = append(infos, info)
+		}),
+	})
+
+	_, err := client.GetEventsByIDsContext(context.Background(), []int{1})
+	require.NoError(t, err)
+
+	_, err = client.GetEventsByIDs([]int{2})
+	require.Error(t, err)
+
+	require.Len(t, infos, 2)
+
+	ok := infos[0]
+	assert.Equal(t, "/events", ok.Endpoint)
+	assert.Equal(t, server.URL+"/events?id=1&limit=1", ok.URL)
+	assert.Equal(t, http.StatusOK, ok.StatusCode)
+	assert.Equal(t, 1, ok.Events)
+	assert.Positive(t, ok.BytesReceived)
+	assert.Equal(t, ok.BytesReceived, ok.BytesDecoded)
+	assert.GreaterOrEqual(t, ok.Duration, ok.Latency)
+	assert.Equal(t, ErrorClassNone, ok.ErrorClass)
+	assert.NoError(t, ok.Err)
+
+	failed := infos[1]
+	assert.Equal(t, ErrorClassValidation, failed.ErrorClass)
+	assert.Equal(t, err, failed.Err)
+}
+
+func TestGetEventsContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not have been sent")
+	}))
+	defer server.Close()
+
+	var info *RequestInfo
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Observer: ObserverFunc(func(ctx context.Context, i *RequestInfo) {
+			info = i
+		}),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response, err := client.GetEventsByKeysetPageContext(ctx, "", 10)
+	require.Error(t, err)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, info)
+	assert.Equal(t, ErrorClassTransport, info.ErrorClass)
+}
+
+func TestMultiObserver(t *testing.T) {
+	var calls []string
+	observer := MultiObserver(
+		ObserverFunc(func(ctx context.Context, info *RequestInfo) { calls = append(calls, "first "+info.Endpoint) }),
+		nil,
+		ObserverFunc(func(ctx context.Context, info *RequestInfo) { calls = append(calls, "second "+info.Endpoint) }),
+	)
+
+	observer.ObserveRequest(context.Background(), &RequestInfo{Endpoint: "/events"})
+
+	assert.Equal(t, []string{"first /events", "second /events"}, calls)
+}
+
+func TestResponseMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old/events/keyset" {
+			http.Redirect(w, r, "/events/keyset?"+r.URL.RawQuery, http.StatusFound)
+			return
+		}
+
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("CF-Ray", "8a1b2c3d4e5f-AMS")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusOK)
+
+		gz := gzip.NewWriter(w)
+		json.NewEncoder(gz).Encode(GetEventsKeysetResponse{
+			Events: []Event{mockEvent("1"), mockEvent("2"), mockEvent("3")},
+		})
+		gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL + "/old",
+	})
+
+	response, err := client.GetEventsByKeysetPage("", 10)
+	require.NoError(t, err)
+	require.NotNil(t, response.Meta)
+
+	meta := response.Meta
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.Equal(t, server.URL+"/events/keyset?limit=10", meta.URL)
+	assert.Equal(t, "8a1b2c3d4e5f-AMS", meta.CFRay())
+	remaining, ok := meta.RateLimitRemaining()
+	assert.True(t, ok)
+	assert.Equal(t, 42, remaining)
+	assert.Positive(t, meta.Latency)
+	assert.Positive(t, meta.BytesReceived)
+	assert.Greater(t, meta.BytesDecoded, meta.BytesReceived)
+}
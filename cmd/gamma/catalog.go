@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func tags(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts   options
+		offset int
+		limit  int
+	)
+	flags := newFlagSet("tags", stderr, &opts)
+	addOutputFlags(flags, &opts, "table")
+	flags.IntVar(&offset, "offset", 0, "number of tags to skip")
+	flags.IntVar(&limit, "limit", 100, "tags per page (at most 100)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma tags [flags]")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	out, err := newOutput(&opts, tagLayout, stdout)
+	if err != nil {
+		return err
+	}
+
+	response, err := opts.client().GetTagsByPageContext(ctx, offset, limit)
+	if err != nil {
+		return err
+	}
+	if err := out.print(response.Tags); err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+func series(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts   options
+		offset int
+		limit  int
+	)
+	flags := newFlagSet("series", stderr, &opts)
+	addOutputFlags(flags, &opts, "table")
+	flags.IntVar(&offset, "offset", 0, "number of series to skip")
+	flags.IntVar(&limit, "limit", 100, "series per page (at most 100)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma series [flags]")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	out, err := newOutput(&opts, seriesLayout, stdout)
+	if err != nil {
+		return err
+	}
+
+	response, err := opts.client().GetSeriesByPageContext(ctx, offset, limit)
+	if err != nil {
+		return err
+	}
+	if err := out.print(response.Series); err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+func search(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts     options
+		expr     string
+		limit    int
+		showTags bool
+	)
+	flags := newFlagSet("search", stderr, &opts)
+	addOutputFlags(flags, &opts, "table")
+	flags.StringVar(&expr, "filter", "", filterUsage)
+	flags.IntVar(&limit, "limit", 20, "maximum results")
+	flags.BoolVar(&showTags, "tags", false, "print the matching tags instead of events")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma search [flags] QUERY...")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+	if showTags && expr != "" {
+		return fmt.Errorf("--filter only applies to events, not --tags")
+	}
+	query := strings.Join(flags.Args(), " ")
+
+	if showTags {
+		out, err := newOutput(&opts, tagLayout, stdout)
+		if err != nil {
+			return err
+		}
+		response, err := opts.client().SearchContext(ctx, query, limit)
+		if err != nil {
+			return err
+		}
+		if err := out.print(response.Tags); err != nil {
+			return err
+		}
+		return out.flush()
+	}
+
+	out, err := newEventOutput(&opts, expr, stdout)
+	if err != nil {
+		return err
+	}
+	response, err := opts.client().SearchContext(ctx, query, limit)
+	if err != nil {
+		return err
+	}
+	if err := out.print(response.Events); err != nil {
+		return err
+	}
+	return out.flush()
+}
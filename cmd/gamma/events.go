@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+const (
+	filterUsage       = `keep only events matching this filter expression, such as 'any(markets, .spread < 0.02)'`
+	marketFilterUsage = `keep only markets matching this filter expression, such as 'spread < 0.02'`
+)
+
+func eventsGet(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts options
+		expr string
+	)
+	flags := newFlagSet("events get", stderr, &opts)
+	addOutputFlags(flags, &opts, "table")
+	flags.StringVar(&expr, "filter", "", filterUsage)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma events get [flags] ID...")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	ids, err := parseIDs("event", flags.Args())
+	if err != nil {
+		return err
+	}
+
+	out, err := newEventOutput(&opts, expr, stdout)
+	if err != nil {
+		return err
+	}
+
+	response, err := opts.client().GetEventsByIDsContext(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := out.print(response.Events); err != nil {
+		return err
+	}
+	if err := out.flush(); err != nil {
+		return err
+	}
+
+	if len(response.MissingIDs) > 0 {
+		return fmt.Errorf("events not found: %v", response.MissingIDs)
+	}
+	return nil
+}
+
+// allFlags are the events list flags that --all supports
+var allFlags = map[string]bool{
+	"all": true, "active": true, "limit": true, "filter": true,
+	"output": true, "o": true, "base-url": true, "timeout": true,
+}
+
+func eventsList(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts     options
+		expr     string
+		query    queryFlags
+		tagSlug  string
+		seriesID int
+		featured optionalBool
+		all      bool
+	)
+	flags := newFlagSet("events list", stderr, &opts)
+	addOutputFlags(flags, &opts, "table")
+	flags.StringVar(&expr, "filter", "", filterUsage)
+	query.register(flags, "events")
+	flags.StringVar(&tagSlug, "tag", "", "only events with this tag slug")
+	flags.IntVar(&seriesID, "series-id", 0, "only events in this series")
+	flags.Var(&featured, "featured", "only featured events, or unfeatured ones with --featured=false")
+	flags.BoolVar(&all, "all", false, "list every event by ascending ID, past the offset cap, with --limit as the page size")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma events list [flags]")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	eventQuery, err := query.eventQuery()
+	if err != nil {
+		return err
+	}
+	eventQuery.TagSlug = tagSlug
+	eventQuery.SeriesID = seriesID
+	eventQuery.Featured = featured.value
+
+	if all {
+		// The pager walks every event in ID order, so only --active narrows it
+		var conflicting []string
+		flags.Visit(func(f *flag.Flag) {
+			if !allFlags[f.Name] {
+				conflicting = append(conflicting, "--"+f.Name)
+			}
+		})
+		if len(conflicting) > 0 {
+			return fmt.Errorf("--all cannot be combined with %s", strings.Join(conflicting, ", "))
+		}
+	}
+
+	out, err := newEventOutput(&opts, expr, stdout)
+	if err != nil {
+		return err
+	}
+	client := opts.client()
+
+	if all {
+		pager := polymarket_gamma.NewPager(client, &polymarket_gamma.PagerConfig{
+			PageSize:   query.limit,
+			ActiveOnly: query.active,
+			OnWarning:  func(message string) { fmt.Fprintf(stderr, "gamma: %s\n", message) },
+		})
+		for page, err := range pager.Pages(ctx) {
+			if err != nil {
+				return err
+			}
+			if err := out.print(page.Events); err != nil {
+				return err
+			}
+		}
+		return out.flush()
+	}
+
+	response, err := client.QueryEventsContext(ctx, eventQuery)
+	if err != nil {
+		return err
+	}
+	if err := out.print(response.Events); err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+func eventsCrawl(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts       options
+		expr       string
+		checkpoint string
+		active     bool
+		pageSize   int
+		progress   bool
+	)
+	flags := newFlagSet("events crawl", stderr, &opts)
+	// Crawls default to NDJSON, which streams and resumes cleanly
+	addOutputFlags(flags, &opts, "ndjson")
+	flags.StringVar(&expr, "filter", "", filterUsage)
+	flags.StringVar(&checkpoint, "checkpoint", "", "file to persist progress in, so an interrupted crawl resumes")
+	flags.BoolVar(&active, "active", false, "only active events")
+	flags.IntVar(&pageSize, "page-size", 100, "events per request")
+	flags.BoolVar(&progress, "progress", false, "report each page on stderr")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma events crawl [flags]")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	out, err := newEventOutput(&opts, expr, stdout)
+	if err != nil {
+		return err
+	}
+
+	crawler := polymarket_gamma.NewCrawler(opts.client(), &polymarket_gamma.CrawlerConfig{
+		CheckpointPath: checkpoint,
+		ActiveOnly:     active,
+		PageSize:       pageSize,
+		Sink: func(ctx context.Context, events []polymarket_gamma.Event) error {
+			return out.print(events)
+		},
+		Progress: func(c polymarket_gamma.CrawlCheckpoint) {
+			if progress {
+				fmt.Fprintf(stderr, "gamma: page %d, %d events\n", c.Pages, c.Events)
+			}
+		},
+	})
+	if err := crawler.Run(ctx); err != nil {
+		out.flush()
+		return err
+	}
+	return out.flush()
+}
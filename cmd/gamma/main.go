@@ -0,0 +1,165 @@
+// Command gamma queries the Polymarket Gamma API from the command line.
+//
+// Usage:
+//
+//	gamma events get [flags] ID...
+//	gamma events list [flags]
+//	gamma events crawl [flags]
+//	gamma markets get [flags] ID...
+//	gamma markets list [flags]
+//	gamma tags [flags]
+//	gamma series [flags]
+//	gamma search [flags] QUERY...
+//	gamma watch (--events ID,... | --tag SLUG) [flags]
+//
+// Every command accepts --base-url (default $GAMMA_BASE_URL or the public API), so it
+// works against a local gammatest server. The list commands expose the API's query
+// parameters as flags, and every command but watch prints with --output table, json,
+// ndjson or csv.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+const usage = `Usage: gamma <command> [flags]
+
+Commands:
+  events get ID...   fetch events by ID
+  events list        list a page of events, or every event with --all
+  events crawl       stream every event through keyset pagination, resumably
+  markets get ID...  fetch markets by ID
+  markets list       list a page of markets
+  tags               list tags
+  series             list series
+  search QUERY...    search events, or tags with --tags
+  watch              poll events or a tag and show their markets' prices live
+
+Run "gamma <command> -h" for the flags of a command.
+`
+
+// errUsage is returned for bad command lines, after the problem has been reported
+var errUsage = errors.New("usage error")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	err := dispatch(ctx, args, stdout, stderr)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "gamma: %v\n", err)
+		return 1
+	}
+}
+
+func dispatch(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	command := args[0]
+	if (command == "events" || command == "markets") && len(args) > 1 {
+		command, args = command+" "+args[1], args[1:]
+	}
+
+	switch command {
+	case "events get":
+		return eventsGet(ctx, args[1:], stdout, stderr)
+	case "events list":
+		return eventsList(ctx, args[1:], stdout, stderr)
+	case "events crawl":
+		return eventsCrawl(ctx, args[1:], stdout, stderr)
+	case "markets get":
+		return marketsGet(ctx, args[1:], stdout, stderr)
+	case "markets list":
+		return marketsList(ctx, args[1:], stdout, stderr)
+	case "tags":
+		return tags(ctx, args[1:], stdout, stderr)
+	case "series":
+		return series(ctx, args[1:], stdout, stderr)
+	case "search":
+		return search(ctx, args[1:], stdout, stderr)
+	case "watch":
+		return watch(ctx, args[1:], stdout, stderr)
+	}
+
+	fmt.Fprintf(stderr, "gamma: unknown command %q\n\n%s", command, usage)
+	return errUsage
+}
+
+// options are the flags shared by every command
+type options struct {
+	baseURL string
+	timeout time.Duration
+	output  string
+}
+
+func newFlagSet(name string, stderr io.Writer, opts *options) *flag.FlagSet {
+	flags := flag.NewFlagSet("gamma "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&opts.baseURL, "base-url", os.Getenv("GAMMA_BASE_URL"), "API base URL (default $GAMMA_BASE_URL or the public API)")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "per-request timeout")
+	return flags
+}
+
+// addOutputFlags registers --output and -o, for the commands that print records
+func addOutputFlags(flags *flag.FlagSet, opts *options, format string) {
+	flags.StringVar(&opts.output, "output", format, "output format: table, json, ndjson or csv")
+	flags.StringVar(&opts.output, "o", format, "shorthand for --output")
+}
+
+// parse parses args, mapping flag errors to errUsage
+func parse(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+// parseIDs parses the numeric IDs of records of kind, such as "event"
+func parseIDs(kind string, args []string) ([]int, error) {
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s ID %q", kind, arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (o *options) client() *polymarket_gamma.Client {
+	return polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL: o.baseURL,
+		Timeout: o.timeout,
+	})
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/gammatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServer(t *testing.T) *gammatest.Server {
+	var events []polymarket_gamma.Event
+	for i := 1; i <= 5; i++ {
+		id := string(rune('0' + i))
+		events = append(events, polymarket_gamma.Event{
+			ID:         id,
+			Slug:       "event-" + id,
+			Title:      "Event " + id,
+			Category:   "Sports",
+			Active:     i%2 == 1,
+			Closed:     i%2 == 0,
+			Volume24hr: float64(i * 1000),
+			EndDate:    time.Date(2026, 11, i, 0, 0, 0, 0, time.UTC),
+			Markets:    []polymarket_gamma.Market{{ID: "m" + id, Spread: float64(i) / 100}},
+		})
+	}
+
+	server := gammatest.NewServer(&gammatest.Config{
+		Events: events,
+		Markets: []polymarket_gamma.Market{
+			{ID: "101", Question: "Will it rain?", ConditionID: "0xa", Active: true, OutcomePrices: `["0.4", "0.6"]`, Spread: 0.02},
+			{ID: "102", Question: "Will it snow?", ConditionID: "0xb", Closed: true, Spread: 0.1},
+		},
+		Tags:   []polymarket_gamma.Tag{{ID: "1", Label: "Sports", Slug: "sports"}, {ID: "2", Label: "Weather", Slug: "weather"}},
+		Series: []polymarket_gamma.Series{{ID: "7", Slug: "nfl", Title: "NFL", Recurrence: "weekly"}},
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+// gamma runs the command line against server and returns the exit status, stdout
+// and stderr
+func gamma(t *testing.T, server *gammatest.Server, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	// The flags follow the command, which is two words for events and markets commands
+	words := 1
+	if args[0] == "events" || args[0] == "markets" {
+		words = 2
+	}
+	if len(args) >= words {
+		args = append(args[:words:words], append([]string{"--base-url", server.URL}, args[words:]...)...)
+	}
+	status := run(context.Background(), args, &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestEventsGet(t *testing.T) {
+	server := testServer(t)
+
+	status, stdout, stderr := gamma(t, server, "events", "get", "-o", "ndjson", "2", "4", "9")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "gamma: events not found: [9]\n", stderr)
+
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var event polymarket_gamma.Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"2", "4"}, ids)
+
+	status, _, stderr = gamma(t, server, "events", "get", "two")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "gamma: invalid event ID \"two\"\n", stderr)
+}
+
+func TestEventsList(t *testing.T) {
+	server := testServer(t)
+
+	status, stdout, _ := gamma(t, server, "events", "list", "--limit", "2", "--ascending", "-o", "csv")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, strings.Join([]string{
+		"id,slug,title,category,active,closed,markets,volume,volume24hr,liquidity,end_date",
+		"1,event-1,Event 1,Sports,true,false,1,0,1000,0,2026-11-01T00:00:00Z",
+		"2,event-2,Event 2,Sports,false,true,1,0,2000,0,2026-11-02T00:00:00Z",
+		"",
+	}, "\n"), stdout)
+
+	status, stdout, _ = gamma(t, server, "events", "list", "--all", "--limit", "2", "--filter", "any(markets, .spread >= 0.03)")
+	assert.Equal(t, 0, status)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 4)
+	assert.Regexp(t, `^ID\s+TITLE\s+ACTIVE\s+CLOSED\s+MARKETS\s+VOLUME 24H\s+LIQUIDITY\s+END DATE$`, lines[0])
+	assert.Regexp(t, `^3\s+Event 3\s+true\s+false\s+1\s+3000\s+0\s+2026-11-03 00:00$`, lines[1])
+
+	status, stdout, _ = gamma(t, server, "events", "list", "--active", "-o", "json")
+	assert.Equal(t, 0, status)
+	var events []polymarket_gamma.Event
+	require.NoError(t, json.Unmarshal([]byte(stdout), &events))
+	assert.Len(t, events, 3)
+}
+
+func TestEventsListQuery(t *testing.T) {
+	server := testServer(t)
+
+	status, stdout, _ := gamma(t, server, "events", "list", "--order", "volume24hr", "--closed=false", "--slug", "event-1,event-3,event-4", "-o", "ndjson")
+	assert.Equal(t, 0, status)
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var event polymarket_gamma.Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"3", "1"}, ids)
+
+	status, stdout, _ = gamma(t, server, "events", "list", "--end-date-max", "2026-11-02", "--ascending", "-o", "csv")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, 3, strings.Count(stdout, "\n"), "header and events 1 and 2")
+	assert.Contains(t, stdout, "\n2,event-2,")
+
+	status, _, _ = gamma(t, server, "events", "list", "--tag-id", "4", "--volume-min", "10.5", "--end-date-max", "2026-12-01", "-o", "json")
+	assert.Equal(t, 0, status)
+	requests := server.Requests()
+	assert.Equal(t, "/events?ascending=false&end_date_max=2026-12-01T00%3A00%3A00Z&limit=20&order=id&tag_id=4&volume_min=10.5", requests[len(requests)-1])
+}
+
+func TestMarkets(t *testing.T) {
+	server := testServer(t)
+
+	status, stdout, stderr := gamma(t, server, "markets", "get", "-o", "csv", "102", "101", "999")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "gamma: markets not found: [999]\n", stderr)
+	assert.Equal(t, strings.Join([]string{
+		"id,slug,question,condition_id,active,closed,outcomes,outcome_prices,best_bid,best_ask,spread,volume24hr,liquidity,end_date",
+		"102,,Will it snow?,0xb,false,true,,,0,0,0.1,0,0,",
+		`101,,Will it rain?,0xa,true,false,,"[""0.4"", ""0.6""]",0,0,0.02,0,0,`,
+		"",
+	}, "\n"), stdout)
+
+	status, stdout, _ = gamma(t, server, "markets", "list", "--active", "--filter", "spread < 0.05", "--limit", "100")
+	assert.Equal(t, 0, status)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 6)
+	assert.Regexp(t, `^ID\s+QUESTION\s+ACTIVE\s+CLOSED\s+PRICES\s+SPREAD\s+VOLUME 24H\s+LIQUIDITY\s+END DATE$`, lines[0])
+	assert.Regexp(t, `^m4\s+false\s+false\s+0\.040\s`, lines[1])
+	assert.Regexp(t, `^101\s+Will it rain\?\s+true\s+false\s+\["0\.4", "0\.6"\]\s+0\.020\s`, lines[5])
+
+	status, stdout, _ = gamma(t, server, "markets", "list", "--condition-id", "0xa", "-o", "ndjson")
+	assert.Equal(t, 0, status)
+	var market polymarket_gamma.Market
+	require.NoError(t, json.Unmarshal([]byte(stdout), &market))
+	assert.Equal(t, "101", market.ID)
+}
+
+func TestTagsSeriesAndSearch(t *testing.T) {
+	server := testServer(t)
+
+	status, stdout, _ := gamma(t, server, "tags", "-o", "csv")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "id,slug,label\n1,sports,Sports\n2,weather,Weather\n", stdout)
+
+	status, stdout, _ = gamma(t, server, "series")
+	assert.Equal(t, 0, status)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^7\s+nfl\s+NFL\s+weekly\s+false\s+false\s+0$`, lines[1])
+
+	status, stdout, _ = gamma(t, server, "search", "-o", "ndjson", "event", "2")
+	assert.Equal(t, 0, status)
+	var event polymarket_gamma.Event
+	require.NoError(t, json.Unmarshal([]byte(stdout), &event))
+	assert.Equal(t, "2", event.ID)
+
+	status, stdout, _ = gamma(t, server, "search", "--tags", "-o", "csv", "WEATH")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "id,slug,label\n2,weather,Weather\n", stdout)
+
+	status, _, _ = gamma(t, server, "search")
+	assert.Equal(t, 2, status)
+}
+
+func TestCommandLineErrors(t *testing.T) {
+	server := testServer(t)
+
+	status, _, stderr := gamma(t, server, "events", "list", "--filter", "any(markets, .sprad < 1)")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "gamma: failed to compile filter: column 14: unknown field \"sprad\" on Market (did you mean \"spread\"?)\n", stderr)
+
+	status, _, stderr = gamma(t, server, "events", "list", "-o", "yaml")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, `unknown output format "yaml"`)
+
+	status, _, stderr = gamma(t, server, "profiles", "1")
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, `unknown command "profiles"`)
+
+	status, _, stderr = gamma(t, server, "events", "list", "--active", "--closed")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "gamma: --active cannot be combined with --closed\n", stderr)
+
+	status, _, stderr = gamma(t, server, "events", "list", "--all", "--order", "volume", "--tag", "nba")
+	assert.Equal(t, 1, status)
+	assert.Equal(t, "gamma: --all cannot be combined with --order, --tag\n", stderr)
+
+	status, _, _ = gamma(t, server, "events", "list", "--end-date-min", "soon")
+	assert.Equal(t, 2, status)
+
+	status, _, _ = gamma(t, server, "events", "list", "--bogus")
+	assert.Equal(t, 2, status)
+
+	status, _, _ = gamma(t, server, "events", "list", "-h")
+	assert.Equal(t, 0, status)
+}
+
+func TestEventsCrawl(t *testing.T) {
+	server := testServer(t)
+	checkpoint := filepath.Join(t.TempDir(), "crawl.json")
+
+	status, stdout, stderr := gamma(t, server, "events", "crawl", "--page-size", "2", "--checkpoint", checkpoint, "--progress", "--filter", "active")
+	assert.Equal(t, 0, status)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 3)
+	assert.Contains(t, stderr, "gamma: page 3, 5 events\n")
+	assert.FileExists(t, checkpoint)
+}
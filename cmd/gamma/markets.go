@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+func marketsGet(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts options
+		expr string
+	)
+	flags := newFlagSet("markets get", stderr, &opts)
+	addOutputFlags(flags, &opts, "table")
+	flags.StringVar(&expr, "filter", "", marketFilterUsage)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma markets get [flags] ID...")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	ids, err := parseIDs("market", flags.Args())
+	if err != nil {
+		return err
+	}
+
+	out, err := newMarketOutput(&opts, expr, stdout)
+	if err != nil {
+		return err
+	}
+
+	response, err := opts.client().GetMarketsByIDsContext(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := out.print(response.Markets); err != nil {
+		return err
+	}
+	if err := out.flush(); err != nil {
+		return err
+	}
+
+	if len(response.MissingIDs) > 0 {
+		return fmt.Errorf("markets not found: %v", response.MissingIDs)
+	}
+	return nil
+}
+
+func marketsList(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts         options
+		expr         string
+		query        queryFlags
+		conditionIDs stringList
+		tokenIDs     stringList
+	)
+	flags := newFlagSet("markets list", stderr, &opts)
+	addOutputFlags(flags, &opts, "table")
+	flags.StringVar(&expr, "filter", "", marketFilterUsage)
+	query.register(flags, "markets")
+	flags.Var(&conditionIDs, "condition-id", "only the market with this condition ID (repeatable, or comma-separated)")
+	flags.Var(&tokenIDs, "token-id", "only the market with this CLOB token ID (repeatable, or comma-separated)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma markets list [flags]")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	marketQuery, err := query.marketQuery()
+	if err != nil {
+		return err
+	}
+	marketQuery.ConditionIDs = conditionIDs
+	marketQuery.ClobTokenIDs = tokenIDs
+
+	out, err := newMarketOutput(&opts, expr, stdout)
+	if err != nil {
+		return err
+	}
+
+	response, err := opts.client().QueryMarketsContext(ctx, marketQuery)
+	if err != nil {
+		return err
+	}
+	if err := out.print(response.Markets); err != nil {
+		return err
+	}
+	return out.flush()
+}
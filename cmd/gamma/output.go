@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/filter"
+)
+
+const maxTitleWidth = 60
+
+// output prints records through opts' format, keeping those that match
+type output[T any] struct {
+	printer printer[T]
+	match   func(T) bool
+}
+
+func newOutput[T any](opts *options, l *layout[T], stdout io.Writer) (*output[T], error) {
+	printer, err := newPrinter(opts.output, l, stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &output[T]{printer: printer}, nil
+}
+
+// newEventOutput prints events, keeping those that match the filter expression expr
+func newEventOutput(opts *options, expr string, stdout io.Writer) (*output[polymarket_gamma.Event], error) {
+	out, err := newOutput(opts, eventLayout, stdout)
+	if err != nil || expr == "" {
+		return out, err
+	}
+	f, err := filter.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	out.match = f.Match
+	return out, nil
+}
+
+// newMarketOutput prints markets, keeping those that match the filter expression expr
+func newMarketOutput(opts *options, expr string, stdout io.Writer) (*output[polymarket_gamma.Market], error) {
+	out, err := newOutput(opts, marketLayout, stdout)
+	if err != nil || expr == "" {
+		return out, err
+	}
+	f, err := filter.CompileMarket(expr)
+	if err != nil {
+		return nil, err
+	}
+	out.match = f.Match
+	return out, nil
+}
+
+func (o *output[T]) print(records []T) error {
+	for _, record := range records {
+		if o.match != nil && !o.match(record) {
+			continue
+		}
+		if err := o.printer.Print(record); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *output[T]) flush() error {
+	if err := o.printer.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// layout describes how one kind of record is printed in the table and CSV formats
+type layout[T any] struct {
+	// kind names a record in error messages, such as "event"
+	kind string
+	id   func(T) string
+	// header and cells are the table columns
+	header []string
+	cells  func(T) []string
+	// columns and row are the CSV columns
+	columns []string
+	row     func(T) []string
+}
+
+var eventLayout = &layout[polymarket_gamma.Event]{
+	kind:   "event",
+	id:     func(e polymarket_gamma.Event) string { return e.ID },
+	header: []string{"ID", "TITLE", "ACTIVE", "CLOSED", "MARKETS", "VOLUME 24H", "LIQUIDITY", "END DATE"},
+	cells: func(e polymarket_gamma.Event) []string {
+		return []string{
+			e.ID,
+			truncate(e.Title, maxTitleWidth),
+			strconv.FormatBool(e.Active),
+			strconv.FormatBool(e.Closed),
+			strconv.Itoa(len(e.Markets)),
+			fmt.Sprintf("%.0f", e.Volume24hr),
+			fmt.Sprintf("%.0f", e.Liquidity),
+			tableDate(e.EndDate),
+		}
+	},
+	columns: []string{"id", "slug", "title", "category", "active", "closed", "markets", "volume", "volume24hr", "liquidity", "end_date"},
+	row: func(e polymarket_gamma.Event) []string {
+		return []string{
+			e.ID,
+			e.Slug,
+			e.Title,
+			e.Category,
+			strconv.FormatBool(e.Active),
+			strconv.FormatBool(e.Closed),
+			strconv.Itoa(len(e.Markets)),
+			formatFloat(e.Volume),
+			formatFloat(e.Volume24hr),
+			formatFloat(e.Liquidity),
+			csvDate(e.EndDate),
+		}
+	},
+}
+
+var marketLayout = &layout[polymarket_gamma.Market]{
+	kind:   "market",
+	id:     func(m polymarket_gamma.Market) string { return m.ID },
+	header: []string{"ID", "QUESTION", "ACTIVE", "CLOSED", "PRICES", "SPREAD", "VOLUME 24H", "LIQUIDITY", "END DATE"},
+	cells: func(m polymarket_gamma.Market) []string {
+		return []string{
+			m.ID,
+			truncate(m.Question, maxTitleWidth),
+			strconv.FormatBool(m.Active),
+			strconv.FormatBool(m.Closed),
+			m.OutcomePrices,
+			fmt.Sprintf("%.3f", m.Spread),
+			fmt.Sprintf("%.0f", m.Volume24hr),
+			fmt.Sprintf("%.0f", m.LiquidityNum),
+			tableDate(m.EndDate),
+		}
+	},
+	columns: []string{"id", "slug", "question", "condition_id", "active", "closed", "outcomes", "outcome_prices", "best_bid", "best_ask", "spread", "volume24hr", "liquidity", "end_date"},
+	row: func(m polymarket_gamma.Market) []string {
+		return []string{
+			m.ID,
+			m.Slug,
+			m.Question,
+			m.ConditionID,
+			strconv.FormatBool(m.Active),
+			strconv.FormatBool(m.Closed),
+			m.Outcomes,
+			m.OutcomePrices,
+			formatFloat(m.BestBid),
+			formatFloat(m.BestAsk),
+			formatFloat(m.Spread),
+			formatFloat(m.Volume24hr),
+			formatFloat(m.LiquidityNum),
+			csvDate(m.EndDate),
+		}
+	},
+}
+
+var tagLayout = &layout[polymarket_gamma.Tag]{
+	kind:   "tag",
+	id:     func(t polymarket_gamma.Tag) string { return t.ID },
+	header: []string{"ID", "SLUG", "LABEL"},
+	cells: func(t polymarket_gamma.Tag) []string {
+		return []string{t.ID, t.Slug, truncate(t.Label, maxTitleWidth)}
+	},
+	columns: []string{"id", "slug", "label"},
+	row: func(t polymarket_gamma.Tag) []string {
+		return []string{t.ID, t.Slug, t.Label}
+	},
+}
+
+var seriesLayout = &layout[polymarket_gamma.Series]{
+	kind:   "series",
+	id:     func(s polymarket_gamma.Series) string { return s.ID },
+	header: []string{"ID", "SLUG", "TITLE", "RECURRENCE", "ACTIVE", "CLOSED", "VOLUME 24H"},
+	cells: func(s polymarket_gamma.Series) []string {
+		return []string{
+			s.ID,
+			s.Slug,
+			truncate(s.Title, maxTitleWidth),
+			s.Recurrence,
+			strconv.FormatBool(s.Active),
+			strconv.FormatBool(s.Closed),
+			fmt.Sprintf("%.0f", s.Volume24hr),
+		}
+	},
+	columns: []string{"id", "slug", "title", "recurrence", "active", "closed", "volume", "volume24hr", "liquidity"},
+	row: func(s polymarket_gamma.Series) []string {
+		return []string{
+			s.ID,
+			s.Slug,
+			s.Title,
+			s.Recurrence,
+			strconv.FormatBool(s.Active),
+			strconv.FormatBool(s.Closed),
+			formatFloat(s.Volume),
+			formatFloat(s.Volume24hr),
+			formatFloat(s.Liquidity),
+		}
+	},
+}
+
+// printer writes records in one output format. Records are written as they are
+// printed where the format allows, so long crawls stream.
+type printer[T any] interface {
+	Print(record T) error
+	// Flush completes the output
+	Flush() error
+}
+
+func newPrinter[T any](format string, l *layout[T], w io.Writer) (printer[T], error) {
+	switch format {
+	case "table":
+		return &tablePrinter[T]{layout: l, w: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}, nil
+	case "json":
+		return &jsonPrinter[T]{layout: l, w: w}, nil
+	case "ndjson":
+		return &ndjsonPrinter[T]{layout: l, encoder: json.NewEncoder(w)}, nil
+	case "csv":
+		return &csvPrinter[T]{layout: l, w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q, want table, json, ndjson or csv", format)
+}
+
+type tablePrinter[T any] struct {
+	layout *layout[T]
+	w      *tabwriter.Writer
+	header bool
+}
+
+func (p *tablePrinter[T]) writeHeader() {
+	if !p.header {
+		fmt.Fprintln(p.w, strings.Join(p.layout.header, "\t"))
+		p.header = true
+	}
+}
+
+func (p *tablePrinter[T]) Print(record T) error {
+	p.writeHeader()
+	_, err := fmt.Fprintln(p.w, strings.Join(p.layout.cells(record), "\t"))
+	return err
+}
+
+func (p *tablePrinter[T]) Flush() error {
+	p.writeHeader()
+	return p.w.Flush()
+}
+
+// jsonPrinter writes a JSON array, one indented record at a time
+type jsonPrinter[T any] struct {
+	layout *layout[T]
+	w      io.Writer
+	count  int
+}
+
+func (p *jsonPrinter[T]) Print(record T) error {
+	data, err := json.MarshalIndent(record, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s %s: %w", p.layout.kind, p.layout.id(record), err)
+	}
+
+	separator := ",\n  "
+	if p.count == 0 {
+		separator = "[\n  "
+	}
+	p.count++
+	_, err = fmt.Fprintf(p.w, "%s%s", separator, data)
+	return err
+}
+
+func (p *jsonPrinter[T]) Flush() error {
+	if p.count == 0 {
+		_, err := io.WriteString(p.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(p.w, "\n]\n")
+	return err
+}
+
+type ndjsonPrinter[T any] struct {
+	layout  *layout[T]
+	encoder *json.Encoder
+}
+
+func (p *ndjsonPrinter[T]) Print(record T) error {
+	if err := p.encoder.Encode(record); err != nil {
+		return fmt.Errorf("failed to encode %s %s: %w", p.layout.kind, p.layout.id(record), err)
+	}
+	return nil
+}
+
+func (p *ndjsonPrinter[T]) Flush() error {
+	return nil
+}
+
+type csvPrinter[T any] struct {
+	layout *layout[T]
+	w      *csv.Writer
+	header bool
+}
+
+func (p *csvPrinter[T]) writeHeader() error {
+	if p.header {
+		return nil
+	}
+	p.header = true
+	return p.w.Write(p.layout.columns)
+}
+
+func (p *csvPrinter[T]) Print(record T) error {
+	if err := p.writeHeader(); err != nil {
+		return err
+	}
+	return p.w.Write(p.layout.row(record))
+}
+
+func (p *csvPrinter[T]) Flush() error {
+	if err := p.writeHeader(); err != nil {
+		return err
+	}
+	p.w.Flush()
+	return p.w.Error()
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func tableDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04")
+}
+
+func csvDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+// queryFlags are the query builder flags shared by events list and markets list
+type queryFlags struct {
+	offset    int
+	limit     int
+	order     string
+	ascending bool
+
+	ids         intList
+	slugs       stringList
+	tagID       int
+	relatedTags bool
+
+	active   bool
+	closed   optionalBool
+	archived optionalBool
+
+	liquidityMin float64
+	liquidityMax float64
+	volumeMin    float64
+	volumeMax    float64
+	startDateMin dateFlag
+	startDateMax dateFlag
+	endDateMin   dateFlag
+	endDateMax   dateFlag
+}
+
+func (q *queryFlags) register(flags *flag.FlagSet, kind string) {
+	flags.IntVar(&q.offset, "offset", 0, "number of "+kind+" to skip (the API rejects offsets above 2500)")
+	flags.IntVar(&q.limit, "limit", 20, kind+" per page (at most 100)")
+	flags.StringVar(&q.order, "order", "id", "sort field, such as id, volume, volume24hr, liquidity, endDate or updatedAt")
+	flags.BoolVar(&q.ascending, "ascending", false, "sort ascending instead of descending")
+
+	flags.Var(&q.ids, "id", "only this ID (repeatable, or comma-separated)")
+	flags.Var(&q.slugs, "slug", "only this slug (repeatable, or comma-separated)")
+	flags.IntVar(&q.tagID, "tag-id", 0, "only "+kind+" with this tag ID")
+	flags.BoolVar(&q.relatedTags, "related-tags", false, "with --tag-id, also match related tags")
+
+	flags.BoolVar(&q.active, "active", false, "only "+kind+" that have not closed (same as --closed=false)")
+	flags.Var(&q.closed, "closed", "only closed "+kind+", or open ones with --closed=false")
+	flags.Var(&q.archived, "archived", "only archived "+kind+", or unarchived ones with --archived=false")
+
+	flags.Float64Var(&q.liquidityMin, "liquidity-min", 0, "minimum liquidity")
+	flags.Float64Var(&q.liquidityMax, "liquidity-max", 0, "maximum liquidity")
+	flags.Float64Var(&q.volumeMin, "volume-min", 0, "minimum volume")
+	flags.Float64Var(&q.volumeMax, "volume-max", 0, "maximum volume")
+	flags.Var(&q.startDateMin, "start-date-min", "earliest start date (RFC 3339 or YYYY-MM-DD)")
+	flags.Var(&q.startDateMax, "start-date-max", "latest start date (RFC 3339 or YYYY-MM-DD)")
+	flags.Var(&q.endDateMin, "end-date-min", "earliest end date (RFC 3339 or YYYY-MM-DD)")
+	flags.Var(&q.endDateMax, "end-date-max", "latest end date (RFC 3339 or YYYY-MM-DD)")
+}
+
+// closedValue folds --active into --closed
+func (q *queryFlags) closedValue() (*bool, error) {
+	if !q.active {
+		return q.closed.value, nil
+	}
+	if q.closed.value != nil && *q.closed.value {
+		return nil, fmt.Errorf("--active cannot be combined with --closed")
+	}
+	closed := false
+	return &closed, nil
+}
+
+func (q *queryFlags) eventQuery() (*polymarket_gamma.EventQuery, error) {
+	closed, err := q.closedValue()
+	if err != nil {
+		return nil, err
+	}
+	return &polymarket_gamma.EventQuery{
+		Offset:       q.offset,
+		Limit:        q.limit,
+		Order:        q.order,
+		Ascending:    q.ascending,
+		IDs:          q.ids,
+		Slugs:        q.slugs,
+		TagID:        q.tagID,
+		RelatedTags:  q.relatedTags,
+		Closed:       closed,
+		Archived:     q.archived.value,
+		LiquidityMin: q.liquidityMin,
+		LiquidityMax: q.liquidityMax,
+		VolumeMin:    q.volumeMin,
+		VolumeMax:    q.volumeMax,
+		StartDateMin: q.startDateMin.Time,
+		StartDateMax: q.startDateMax.Time,
+		EndDateMin:   q.endDateMin.Time,
+		EndDateMax:   q.endDateMax.Time,
+	}, nil
+}
+
+func (q *queryFlags) marketQuery() (*polymarket_gamma.MarketQuery, error) {
+	closed, err := q.closedValue()
+	if err != nil {
+		return nil, err
+	}
+	return &polymarket_gamma.MarketQuery{
+		Offset:       q.offset,
+		Limit:        q.limit,
+		Order:        q.order,
+		Ascending:    q.ascending,
+		IDs:          q.ids,
+		Slugs:        q.slugs,
+		TagID:        q.tagID,
+		RelatedTags:  q.relatedTags,
+		Closed:       closed,
+		Archived:     q.archived.value,
+		LiquidityMin: q.liquidityMin,
+		LiquidityMax: q.liquidityMax,
+		VolumeMin:    q.volumeMin,
+		VolumeMax:    q.volumeMax,
+		StartDateMin: q.startDateMin.Time,
+		StartDateMax: q.startDateMax.Time,
+		EndDateMin:   q.endDateMin.Time,
+		EndDateMax:   q.endDateMax.Time,
+	}, nil
+}
+
+// stringList is a repeatable flag whose values may also be comma-separated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// intList is a repeatable flag of integers whose values may also be comma-separated
+type intList []int
+
+func (l *intList) String() string {
+	values := make([]string, len(*l))
+	for i, n := range *l {
+		values[i] = strconv.Itoa(n)
+	}
+	return strings.Join(values, ",")
+}
+
+func (l *intList) Set(value string) error {
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		*l = append(*l, n)
+	}
+	return nil
+}
+
+// optionalBool is a boolean flag that stays nil unless it is given
+type optionalBool struct {
+	value *bool
+}
+
+func (b *optionalBool) String() string {
+	if b.value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b.value)
+}
+
+func (b *optionalBool) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	b.value = &v
+	return nil
+}
+
+func (b *optionalBool) IsBoolFlag() bool {
+	return true
+}
+
+// dateFlag is a time flag taking RFC 3339 or YYYY-MM-DD
+type dateFlag struct {
+	time.Time
+}
+
+func (d *dateFlag) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(time.RFC3339)
+}
+
+func (d *dateFlag) Set(value string) error {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			d.Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q, want RFC 3339 or YYYY-MM-DD", value)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/filter"
+)
+
+const (
+	ansiReset      = "\x1b[0m"
+	ansiBold       = "\x1b[1m"
+	ansiRed        = "\x1b[31m"
+	ansiGreen      = "\x1b[32m"
+	ansiClearHome  = "\x1b[H\x1b[2J"
+	maxQuestionLen = 50
+	maxEventLen    = 30
+)
+
+func watch(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var (
+		opts     options
+		expr     string
+		eventIDs string
+		tag      string
+		interval time.Duration
+		polls    int
+		plain    bool
+	)
+	flags := newFlagSet("watch", stderr, &opts)
+	flags.StringVar(&eventIDs, "events", "", "comma-separated IDs of the events to watch")
+	flags.StringVar(&tag, "tag", "", "watch the active events with this tag slug")
+	flags.StringVar(&expr, "filter", "", "keep only markets matching this filter expression, such as 'spread < 0.05'")
+	flags.DurationVar(&interval, "interval", 10*time.Second, "time between polls")
+	flags.IntVar(&polls, "count", 0, "stop after this many polls (default forever)")
+	flags.BoolVar(&plain, "plain", false, "print changes as lines even on a terminal")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gamma watch (--events ID,... | --tag SLUG) [flags]")
+		flags.PrintDefaults()
+	}
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 || (eventIDs == "") == (tag == "") {
+		flags.Usage()
+		return errUsage
+	}
+
+	var query polymarket_gamma.WatchQuery
+	if eventIDs != "" {
+		var ids []int
+		for _, field := range strings.Split(eventIDs, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return fmt.Errorf("invalid event ID %q", field)
+			}
+			ids = append(ids, id)
+		}
+		query = polymarket_gamma.WatchEventIDs(ids...)
+	} else {
+		query = polymarket_gamma.WatchTag(tag)
+	}
+
+	view := &watchView{start: map[string]float64{}, previous: map[string]marketRow{}}
+	if expr != "" {
+		var err error
+		if view.filter, err = filter.CompileMarket(expr); err != nil {
+			return err
+		}
+	}
+
+	terminal := !plain && isTerminal(stdout)
+	client := opts.client()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for poll := 1; ; poll++ {
+		events, err := query(ctx, client)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		now := time.Now()
+		// A failed poll leaves the last rows on screen
+		rows := view.rows
+		if err == nil {
+			rows = view.update(events)
+		}
+		switch {
+		case terminal:
+			view.renderTable(stdout, rows, now, interval, err)
+		case err != nil:
+			fmt.Fprintf(stderr, "gamma: poll failed: %v\n", err)
+		default:
+			view.renderLines(stdout, rows, now)
+		}
+		if err == nil {
+			view.commit(rows)
+		}
+
+		if polls > 0 && poll >= polls {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// marketRow is one market's line in the watch view
+type marketRow struct {
+	ID        string
+	Event     string
+	Question  string
+	Price     float64
+	Bid       float64
+	Ask       float64
+	Spread    float64
+	Volume24h float64
+	// Change is the price move since the market was first seen
+	Change float64
+	// Move is the price move since the previous poll
+	Move float64
+}
+
+// watchView tracks prices across polls
+type watchView struct {
+	filter   *filter.Filter[polymarket_gamma.Market]
+	start    map[string]float64
+	previous map[string]marketRow
+	rows     []marketRow
+}
+
+// update builds the rows for a poll
+func (v *watchView) update(events []polymarket_gamma.Event) []marketRow {
+	var rows []marketRow
+	for _, event := range events {
+		for _, market := range event.Markets {
+			if v.filter != nil && !v.filter.Match(market) {
+				continue
+			}
+
+			row := marketRow{
+				ID:        market.ID,
+				Event:     event.Title,
+				Question:  market.Question,
+				Price:     market.LastTradePrice,
+				Bid:       market.BestBid,
+				Ask:       market.BestAsk,
+				Spread:    market.Spread,
+				Volume24h: market.Volume24hr,
+			}
+
+			start, seen := v.start[market.ID]
+			if !seen {
+				start = row.Price
+			}
+			row.Change = row.Price - start
+			if previous, ok := v.previous[market.ID]; ok {
+				row.Move = row.Price - previous.Price
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// commit records a poll's rows as the baseline for the next
+func (v *watchView) commit(rows []marketRow) {
+	for _, row := range rows {
+		if _, ok := v.start[row.ID]; !ok {
+			v.start[row.ID] = row.Price
+		}
+		v.previous[row.ID] = row
+	}
+	v.rows = rows
+}
+
+// renderLines prints one line per market that is new or whose quote changed
+func (v *watchView) renderLines(w io.Writer, rows []marketRow, now time.Time) {
+	for _, row := range rows {
+		previous, ok := v.previous[row.ID]
+		if ok && previous.Price == row.Price && previous.Bid == row.Bid && previous.Ask == row.Ask {
+			continue
+		}
+		fmt.Fprintf(w, "%s market=%s %q price=%s bid=%s ask=%s spread=%s vol24h=%.0f change=%s\n",
+			now.UTC().Format(time.RFC3339), row.ID, row.Question,
+			price(row.Price), price(row.Bid), price(row.Ask), price(row.Spread), row.Volume24h, signed(row.Change))
+	}
+}
+
+// cell is a table cell and the ANSI color it is drawn in
+type cell struct {
+	text  string
+	color string
+	right bool
+}
+
+// renderTable redraws the whole table, coloring price moves since the previous poll
+// and the change since the start
+func (v *watchView) renderTable(w io.Writer, rows []marketRow, now time.Time, interval time.Duration, pollErr error) {
+	var b strings.Builder
+	b.WriteString(ansiClearHome)
+	fmt.Fprintf(&b, "%sgamma watch%s  %d markets  updated %s  every %s\n\n", ansiBold, ansiReset, len(rows), now.Format("15:04:05"), interval)
+
+	table := [][]cell{{
+		{text: "MARKET"}, {text: "EVENT"}, {text: "QUESTION"}, {text: "PRICE", right: true}, {text: "BID", right: true},
+		{text: "ASK", right: true}, {text: "SPREAD", right: true}, {text: "VOL 24H", right: true}, {text: "CHANGE", right: true},
+	}}
+	for i := range table[0] {
+		table[0][i].color = ansiBold
+	}
+	for _, row := range rows {
+		table = append(table, []cell{
+			{text: row.ID},
+			{text: truncate(row.Event, maxEventLen)},
+			{text: truncate(row.Question, maxQuestionLen)},
+			{text: price(row.Price), color: moveColor(row.Move), right: true},
+			{text: price(row.Bid), right: true},
+			{text: price(row.Ask), right: true},
+			{text: price(row.Spread), right: true},
+			{text: strconv.FormatFloat(row.Volume24h, 'f', 0, 64), right: true},
+			{text: signed(row.Change), color: moveColor(row.Change), right: true},
+		})
+	}
+	writeTable(&b, table)
+
+	if pollErr != nil {
+		fmt.Fprintf(&b, "\n%spoll failed: %v%s\n", ansiRed, pollErr, ansiReset)
+	}
+	io.WriteString(w, b.String())
+}
+
+// writeTable pads cells to their column width by visible length, so the color codes
+// don't upset the alignment
+func writeTable(b *strings.Builder, table [][]cell) {
+	var widths []int
+	for _, row := range table {
+		for i, c := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+		}
+	}
+
+	for _, row := range table {
+		for i, c := range row {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text))
+			if c.right {
+				b.WriteString(padding)
+			}
+			if c.color != "" {
+				b.WriteString(c.color + c.text + ansiReset)
+			} else {
+				b.WriteString(c.text)
+			}
+			if !c.right && i < len(row)-1 {
+				b.WriteString(padding)
+			}
+		}
+		b.WriteByte('\n')
+	}
+}
+
+func moveColor(move float64) string {
+	switch {
+	case move > 0:
+		return ansiGreen
+	case move < 0:
+		return ansiRed
+	}
+	return ""
+}
+
+func price(p float64) string {
+	return strconv.FormatFloat(p, 'f', 3, 64)
+}
+
+func signed(p float64) string {
+	if p == 0 {
+		return "0.000"
+	}
+	return fmt.Sprintf("%+.3f", p)
+}
+
+// isTerminal reports whether w is a terminal, where the table can be redrawn in place
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
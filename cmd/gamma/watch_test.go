@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/gammatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func quotedEvent(price, bid, ask float64) polymarket_gamma.Event {
+	return polymarket_gamma.Event{
+		ID:    "7",
+		Title: "Lakers vs Celtics",
+		Tags:  []polymarket_gamma.Tag{{ID: "t1", Slug: "nba"}},
+		Markets: []polymarket_gamma.Market{
+			{ID: "m1", Question: "Lakers win?", LastTradePrice: price, BestBid: bid, BestAsk: ask, Spread: ask - bid, Volume24hr: 1500},
+			{ID: "m2", Question: "Over 210.5?", LastTradePrice: 0.5, BestBid: 0.49, BestAsk: 0.51, Spread: 0.02},
+		},
+	}
+}
+
+func TestWatchView(t *testing.T) {
+	view := &watchView{start: map[string]float64{}, previous: map[string]marketRow{}}
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	var lines bytes.Buffer
+	rows := view.update([]polymarket_gamma.Event{quotedEvent(0.5, 0.49, 0.51)})
+	view.renderLines(&lines, rows, at)
+	view.commit(rows)
+
+	rows = view.update([]polymarket_gamma.Event{quotedEvent(0.45, 0.44, 0.46)})
+	view.renderLines(&lines, rows, at.Add(time.Minute))
+
+	assert.Equal(t, strings.Join([]string{
+		`2026-10-18T12:00:00Z market=m1 "Lakers win?" price=0.500 bid=0.490 ask=0.510 spread=0.020 vol24h=1500 change=0.000`,
+		`2026-10-18T12:00:00Z market=m2 "Over 210.5?" price=0.500 bid=0.490 ask=0.510 spread=0.020 vol24h=0 change=0.000`,
+		`2026-10-18T12:01:00Z market=m1 "Lakers win?" price=0.450 bid=0.440 ask=0.460 spread=0.020 vol24h=1500 change=-0.050`,
+		"",
+	}, "\n"), lines.String(), "unchanged markets are not repeated")
+
+	var table bytes.Buffer
+	view.renderTable(&table, rows, at, 10*time.Second, nil)
+	output := table.String()
+	assert.True(t, strings.HasPrefix(output, ansiClearHome))
+	assert.Contains(t, output, "2 markets")
+	assert.Contains(t, output, ansiRed+"0.450"+ansiReset, "the price fell since the last poll")
+	assert.Contains(t, output, ansiRed+"-0.050"+ansiReset)
+	assert.Contains(t, output, "Lakers vs Celtics  Lakers win?")
+
+	// Columns line up regardless of the color codes around some cells
+	var widths []int
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n")[2:] {
+		plain := strings.NewReplacer(ansiBold, "", ansiReset, "", ansiRed, "", ansiGreen, "").Replace(line)
+		widths = append(widths, len(plain))
+	}
+	assert.Equal(t, widths[0], widths[1])
+	assert.Equal(t, widths[0], widths[2])
+}
+
+func TestWatchCommand(t *testing.T) {
+	tagged := quotedEvent(0.5, 0.49, 0.51)
+	other := quotedEvent(0.5, 0.49, 0.51)
+	other.ID = "8"
+	other.Tags = nil
+	other.Markets = []polymarket_gamma.Market{{ID: "m3", Question: "Other?"}}
+
+	server := gammatest.NewServer(&gammatest.Config{Events: []polymarket_gamma.Event{tagged, other}})
+	defer server.Close()
+
+	status, stdout, _ := gamma(t, server, "watch", "--events", "7,8", "--count", "1", "--filter", "spread > 0.01")
+	assert.Equal(t, 0, status)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 2, "m3 is filtered out")
+
+	status, stdout, _ = gamma(t, server, "watch", "--tag", "nba", "--count", "2", "--interval", "1ms")
+	assert.Equal(t, 0, status)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2, "the second poll changed nothing")
+	assert.Contains(t, lines[0], "market=m1")
+
+	status, _, stderr := gamma(t, server, "watch", "--count", "1")
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr, "Usage: gamma watch")
+}
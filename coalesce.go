@@ -0,0 +1,83 @@
+package polymarket_gamma
+
+import (
+	"bytes"
+	"context"
+	"sync"
+)
+
+// flightGroup tracks in-flight requests so identical concurrent requests share one
+// round trip
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	info RequestInfo
+	body []byte
+	err  error
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: map[string]*flightCall{}}
+}
+
+// join returns the in-flight call for key, or registers a new one and reports that
+// the caller is its leader
+func (g *flightGroup) join(key string, info *RequestInfo) (*flightCall, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call, false
+	}
+
+	call := &flightCall{done: make(chan struct{}), info: *info}
+	g.calls[key] = call
+	return call, true
+}
+
+func (g *flightGroup) finish(key string, call *flightCall) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+}
+
+// fetchCoalesced shares one fetch between every concurrent caller asking for the
+// same endpoint and normalised query. The shared fetch is detached from any single
+// caller's context, so one caller giving up does not fail the others; each caller
+// still stops waiting when its own context is done.
+//
+// Every caller gets its own copy of the body, so the slices decoded from it (and Raw)
+// are never shared between callers.
+func (c *Client) fetchCoalesced(ctx context.Context, info *RequestInfo) ([]byte, error) {
+	key := cacheKey(info.Endpoint, info.Query)
+	call, leader := c.flights.join(key, info)
+	if leader {
+		go func() {
+			call.body, call.err = c.fetchOnce(context.WithoutCancel(ctx), &call.info)
+			c.flights.finish(key, call)
+		}()
+	}
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		info.ErrorClass = ErrorClassTransport
+		return nil, ctx.Err()
+	}
+
+	start := info.Start
+	*info = call.info
+	info.Start = start
+	info.Header = info.Header.Clone()
+	info.Coalesced = !leader
+
+	if call.err != nil {
+		return nil, call.err
+	}
+	return bytes.Clone(call.body), nil
+}
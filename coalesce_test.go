@@ -0,0 +1,133 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGatedServer holds every request until release is closed
+func newGatedServer(t *testing.T, hits *atomic.Int32, release chan struct{}) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		json.NewEncoder(w).Encode([]Event{mockEvent(r.URL.Query().Get("id"))})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCoalesceIdenticalRequests(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := newGatedServer(t, &hits, release)
+
+	client := NewClient(&ClientConfig{
+		BaseURL:          server.URL,
+		CoalesceRequests: true,
+		RetainRawBody:    true,
+	})
+
+	const callers = 10
+	responses := make([]*GetEventsResponse, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := client.GetEventsByIDs([]int{1})
+			assert.NoError(t, err)
+			responses[i] = response
+		}(i)
+	}
+
+	require.Eventually(t, func() bool { return hits.Load() == 1 }, time.Second, time.Millisecond)
+	// Give the remaining callers time to join the in-flight request
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), hits.Load())
+
+	coalesced := 0
+	for _, response := range responses {
+		require.NotNil(t, response)
+		if response.Meta.Coalesced {
+			coalesced++
+		}
+	}
+	assert.Equal(t, callers-1, coalesced)
+
+	// Mutating one caller's result must not affect another's
+	responses[0].Events[0].Title = "mutated"
+	responses[0].Events[0].Markets[0].Question = "mutated"
+	responses[0].Raw[0] = 'X'
+	assert.Equal(t, "Test Event", responses[1].Events[0].Title)
+	assert.Equal(t, "Will this happen?", responses[1].Events[0].Markets[0].Question)
+	assert.Equal(t, byte('['), responses[1].Raw[0])
+}
+
+func TestCoalesceKeepsDifferentRequestsApart(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	close(release)
+	server := newGatedServer(t, &hits, release)
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, CoalesceRequests: true})
+
+	var wg sync.WaitGroup
+	for _, id := range []int{1, 2} {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			response, err := client.GetEventsByIDs([]int{id})
+			assert.NoError(t, err)
+			assert.False(t, response.Meta.Coalesced)
+		}(id)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), hits.Load())
+}
+
+func TestCoalesceCallerCancellation(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := newGatedServer(t, &hits, release)
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, CoalesceRequests: true})
+
+	// The first caller gives up, but the shared request carries on for the second
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, err := client.GetEventsByIDsContext(ctx, []int{1})
+		leaderDone <- err
+	}()
+	require.Eventually(t, func() bool { return hits.Load() == 1 }, time.Second, time.Millisecond)
+
+	followerDone := make(chan *GetEventsResponse)
+	go func() {
+		response, err := client.GetEventsByIDs([]int{1})
+		assert.NoError(t, err)
+		followerDone <- response
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+	close(release)
+	response := <-followerDone
+	require.NotNil(t, response)
+	assert.True(t, response.Meta.Coalesced)
+	assert.Equal(t, int32(1), hits.Load())
+}
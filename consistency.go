@@ -0,0 +1,139 @@
+package polymarket_gamma
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ConsistencyReport summarises what a ConsistencyChecker saw during a crawl
+type ConsistencyReport struct {
+	// Events is the number of events observed, duplicates included
+	Events int
+	// Unique is the number of distinct event IDs observed
+	Unique int
+	// Duplicates lists the IDs observed more than once
+	Duplicates []int
+	// OutOfOrder lists the IDs that were not greater than the ID before them, as
+	// keyset pages are documented to be in ascending ID order
+	OutOfOrder []int
+	// Missing lists the IDs in the prior snapshot that were not observed
+	Missing []int
+	// Added lists the observed IDs that were not in the prior snapshot
+	Added []int
+	// InvalidIDs lists the IDs that are not numeric
+	InvalidIDs []string
+	// Partial is set when the checker only saw part of the crawl, such as a crawl
+	// resumed from a checkpoint. Missing is not computed for partial crawls.
+	Partial bool
+}
+
+// Consistent reports whether the crawl had no duplicates, ordering problems or
+// invalid IDs. Missing and added IDs are expected as the catalogue changes, so they
+// are left for the caller to judge.
+func (r ConsistencyReport) Consistent() bool {
+	return len(r.Duplicates) == 0 && len(r.OutOfOrder) == 0 && len(r.InvalidIDs) == 0
+}
+
+// ConsistencyChecker tracks the event IDs seen during a crawl to detect duplicated,
+// out-of-order and missing rows. It is safe for concurrent use.
+type ConsistencyChecker struct {
+	mu         sync.Mutex
+	prior      map[int]bool
+	seen       map[int]int
+	lastID     int
+	events     int
+	outOfOrder []int
+	invalidIDs []string
+	partial    bool
+}
+
+// NewConsistencyChecker returns a checker that compares the crawl against priorIDs,
+// typically the IDs from the previous crawl. priorIDs may be nil.
+func NewConsistencyChecker(priorIDs []int) *ConsistencyChecker {
+	checker := &ConsistencyChecker{seen: map[int]int{}}
+	if priorIDs != nil {
+		checker.prior = make(map[int]bool, len(priorIDs))
+		for _, id := range priorIDs {
+			checker.prior[id] = true
+		}
+	}
+	return checker
+}
+
+// Observe records a page of events in the order they were received
+func (c *ConsistencyChecker) Observe(events []Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, event := range events {
+		c.events++
+
+		id, err := strconv.Atoi(event.ID)
+		if err != nil {
+			c.invalidIDs = append(c.invalidIDs, event.ID)
+			continue
+		}
+
+		c.seen[id]++
+		if c.events > 1 && id <= c.lastID {
+			c.outOfOrder = append(c.outOfOrder, id)
+		}
+		c.lastID = id
+	}
+}
+
+// SeenIDs returns every distinct ID observed, sorted, for use as the next crawl's
+// prior snapshot
+func (c *ConsistencyChecker) SeenIDs() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids := make([]int, 0, len(c.seen))
+	for id := range c.seen {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Report summarises everything observed so far
+func (c *ConsistencyChecker) Report() ConsistencyReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := ConsistencyReport{
+		Events:     c.events,
+		Unique:     len(c.seen),
+		OutOfOrder: slices.Clone(c.outOfOrder),
+		InvalidIDs: slices.Clone(c.invalidIDs),
+		Partial:    c.partial,
+	}
+
+	for id, count := range c.seen {
+		if count > 1 {
+			report.Duplicates = append(report.Duplicates, id)
+		}
+		if c.prior != nil && !c.prior[id] {
+			report.Added = append(report.Added, id)
+		}
+	}
+	if c.prior != nil && !c.partial {
+		for id := range c.prior {
+			if c.seen[id] == 0 {
+				report.Missing = append(report.Missing, id)
+			}
+		}
+	}
+
+	slices.Sort(report.Duplicates)
+	slices.Sort(report.Added)
+	slices.Sort(report.Missing)
+	return report
+}
+
+func (c *ConsistencyChecker) markPartial() {
+	c.mu.Lock()
+	c.partial = true
+	c.mu.Unlock()
+}
@@ -0,0 +1,84 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventsWithIDs(ids ...string) []Event {
+	events := make([]Event, len(ids))
+	for i, id := range ids {
+		events[i] = mockEvent(id)
+	}
+	return events
+}
+
+func TestConsistencyCheckerReport(t *testing.T) {
+	checker := NewConsistencyChecker([]int{1, 2, 3, 4, 5})
+	checker.Observe(eventsWithIDs("1", "2", "4"))
+	checker.Observe(eventsWithIDs("4", "3", "6", "abc"))
+
+	report := checker.Report()
+	assert.Equal(t, ConsistencyReport{
+		Events:     7,
+		Unique:     5,
+		Duplicates: []int{4},
+		OutOfOrder: []int{4, 3},
+		Missing:    []int{5},
+		Added:      []int{6},
+		InvalidIDs: []string{"abc"},
+	}, report)
+	assert.False(t, report.Consistent())
+	assert.Equal(t, []int{1, 2, 3, 4, 6}, checker.SeenIDs())
+}
+
+func TestConsistencyCheckerWithoutPrior(t *testing.T) {
+	checker := NewConsistencyChecker(nil)
+	checker.Observe(eventsWithIDs("1", "2", "3"))
+
+	report := checker.Report()
+	assert.True(t, report.Consistent())
+	assert.Nil(t, report.Missing)
+	assert.Nil(t, report.Added)
+}
+
+func TestCrawlerConsistencyReport(t *testing.T) {
+	api := &keysetAPI{total: 6, failAt: "2"}
+	path := filepath.Join(t.TempDir(), "crawl.json")
+
+	var prior []int
+	for id := 1; id <= 7; id++ {
+		prior = append(prior, id)
+	}
+
+	var report *ConsistencyReport
+	newConfig := func() *CrawlerConfig {
+		return &CrawlerConfig{
+			CheckpointPath: path,
+			PageSize:       2,
+			Sink:           func(context.Context, []Event) error { return nil },
+			Consistency:    NewConsistencyChecker(prior),
+			OnReport:       func(r ConsistencyReport) { report = &r },
+		}
+	}
+
+	// An interrupted run reports nothing; the resumed one only saw part of the crawl
+	require.Error(t, NewCrawler(api, newConfig()).Run(context.Background()))
+	assert.Nil(t, report)
+	require.NoError(t, NewCrawler(api, newConfig()).Run(context.Background()))
+	require.NotNil(t, report)
+	assert.True(t, report.Partial)
+	assert.Nil(t, report.Missing)
+
+	// A full crawl reports the gap against the prior snapshot
+	require.NoError(t, NewCrawler(api, newConfig()).Run(context.Background()))
+	assert.False(t, report.Partial)
+	assert.True(t, report.Consistent())
+	assert.Equal(t, 6, report.Unique)
+	assert.Equal(t, []int{7}, report.Missing)
+	assert.Equal(t, 6, report.Events)
+}
@@ -0,0 +1,190 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultCrawlPageSize = 100
+
+// CrawlCheckpoint records how far a crawl has got. It is persisted after every page
+// that was delivered to the sink, so a restarted crawl resumes from Cursor.
+type CrawlCheckpoint struct {
+	// Cursor is the keyset cursor of the next page to fetch
+	Cursor     string    `json:"cursor"`
+	ActiveOnly bool      `json:"active_only"`
+	Pages      int       `json:"pages"`
+	Events     int       `json:"events"`
+	StartedAt  time.Time `json:"started_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Complete is set once the last page has been delivered
+	Complete bool `json:"complete"`
+}
+
+type CrawlerConfig struct {
+	// CheckpointPath is where progress is persisted (optional). Without it every
+	// Run starts from the beginning.
+	CheckpointPath string
+	// ActiveOnly crawls only active events
+	ActiveOnly bool
+	// PageSize is the number of events requested per page (default 100)
+	PageSize int
+	// Sink receives every page of events. The checkpoint only advances once Sink
+	// returns nil, so after a crash a page may be delivered again but never skipped.
+	Sink func(ctx context.Context, events []Event) error
+	// Progress is called after every page with the updated checkpoint (optional)
+	Progress func(CrawlCheckpoint)
+	// Consistency observes every page the sink accepts (optional). Its report flags
+	// duplicates, ordering problems and gaps; a resumed crawl is reported as partial.
+	Consistency *ConsistencyChecker
+	// OnReport receives Consistency's report once the crawl completes (optional)
+	OnReport func(ConsistencyReport)
+}
+
+// Crawler enumerates every event through keyset pagination, persisting a checkpoint
+// after each page so an interrupted crawl can resume where it stopped
+type Crawler struct {
+	api    EventsAPI
+	config CrawlerConfig
+}
+
+func NewCrawler(api EventsAPI, config *CrawlerConfig) *Crawler {
+	if config == nil {
+		config = &CrawlerConfig{}
+	}
+
+	crawler := &Crawler{api: api, config: *config}
+	if crawler.config.PageSize <= 0 {
+		crawler.config.PageSize = defaultCrawlPageSize
+	}
+	return crawler
+}
+
+// Run crawls until the last page has been delivered, ctx is done or the sink fails.
+// It resumes from the checkpoint if one exists; a checkpoint from a completed crawl
+// starts a new one.
+func (c *Crawler) Run(ctx context.Context) error {
+	if c.config.Sink == nil {
+		return errors.New("crawler has no sink")
+	}
+
+	checkpoint, err := c.loadCheckpoint()
+	if err != nil {
+		return err
+	}
+	if c.config.Consistency != nil && checkpoint.Pages > 0 {
+		c.config.Consistency.markPartial()
+	}
+
+	for !checkpoint.Complete {
+		page, err := c.fetchPage(ctx, checkpoint.Cursor)
+		if err != nil {
+			return fmt.Errorf("failed to fetch page %d: %w", checkpoint.Pages+1, err)
+		}
+
+		if err := c.config.Sink(ctx, page.Events); err != nil {
+			return fmt.Errorf("failed to deliver page %d: %w", checkpoint.Pages+1, err)
+		}
+		if c.config.Consistency != nil {
+			c.config.Consistency.Observe(page.Events)
+		}
+
+		checkpoint.Cursor = page.NextCursor
+		checkpoint.Pages++
+		checkpoint.Events += len(page.Events)
+		checkpoint.UpdatedAt = time.Now()
+		checkpoint.Complete = page.NextCursor == ""
+
+		if err := c.saveCheckpoint(checkpoint); err != nil {
+			return err
+		}
+		if c.config.Progress != nil {
+			c.config.Progress(*checkpoint)
+		}
+	}
+
+	if c.config.Consistency != nil && c.config.OnReport != nil {
+		c.config.OnReport(c.config.Consistency.Report())
+	}
+
+	return nil
+}
+
+func (c *Crawler) fetchPage(ctx context.Context, cursor string) (*GetEventsKeysetResponse, error) {
+	if c.config.ActiveOnly {
+		return c.api.GetActiveEventsByKeysetPageContext(ctx, cursor, c.config.PageSize)
+	}
+	return c.api.GetEventsByKeysetPageContext(ctx, cursor, c.config.PageSize)
+}
+
+// loadCheckpoint reads the persisted checkpoint, or returns a fresh one when there
+// is none to resume from
+func (c *Crawler) loadCheckpoint() (*CrawlCheckpoint, error) {
+	fresh := &CrawlCheckpoint{ActiveOnly: c.config.ActiveOnly, StartedAt: time.Now()}
+	if c.config.CheckpointPath == "" {
+		return fresh, nil
+	}
+
+	data, err := os.ReadFile(c.config.CheckpointPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint CrawlCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", c.config.CheckpointPath, err)
+	}
+	if checkpoint.Complete {
+		return fresh, nil
+	}
+	if checkpoint.ActiveOnly != c.config.ActiveOnly {
+		return nil, fmt.Errorf("checkpoint %s was written with active only %t", c.config.CheckpointPath, checkpoint.ActiveOnly)
+	}
+
+	return &checkpoint, nil
+}
+
+// saveCheckpoint atomically replaces the checkpoint file
+func (c *Crawler) saveCheckpoint(checkpoint *CrawlCheckpoint) error {
+	if c.config.CheckpointPath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	dir := filepath.Dir(c.config.CheckpointPath)
+	tmp, err := os.CreateTemp(dir, filepath.Base(c.config.CheckpointPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.config.CheckpointPath); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
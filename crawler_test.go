@@ -0,0 +1,175 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keysetAPI is an EventsAPI serving events 1..total through keyset pagination, with
+// odd IDs active. Its cursor is the last ID of the previous page.
+type keysetAPI struct {
+	EventsAPI
+
+	mu    sync.Mutex
+	total int
+	calls []string
+	// failAt fails the request for this cursor once
+	failAt string
+}
+
+func (k *keysetAPI) page(afterCursor string, limit int, activeOnly bool) (*GetEventsKeysetResponse, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.calls = append(k.calls, afterCursor)
+	if k.failAt != "" && afterCursor == k.failAt {
+		k.failAt = ""
+		return nil, errors.New("connection reset")
+	}
+
+	after := 0
+	if afterCursor != "" {
+		after, _ = strconv.Atoi(afterCursor)
+	}
+
+	response := &GetEventsKeysetResponse{}
+	last := after
+	for id := after + 1; id <= k.total && len(response.Events) < limit; id++ {
+		last = id
+		if activeOnly && id%2 == 0 {
+			continue
+		}
+		response.Events = append(response.Events, mockEvent(strconv.Itoa(id)))
+	}
+	if last < k.total {
+		response.NextCursor = strconv.Itoa(last)
+	}
+	return response, nil
+}
+
+func (k *keysetAPI) GetEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*GetEventsKeysetResponse, error) {
+	return k.page(afterCursor, limit, false)
+}
+
+func (k *keysetAPI) GetActiveEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*GetEventsKeysetResponse, error) {
+	return k.page(afterCursor, limit, true)
+}
+
+func collectIDs(ids *[]string) func(context.Context, []Event) error {
+	return func(ctx context.Context, events []Event) error {
+		for _, event := range events {
+			*ids = append(*ids, event.ID)
+		}
+		return nil
+	}
+}
+
+func TestCrawlerResumesFromCheckpoint(t *testing.T) {
+	api := &keysetAPI{total: 10, failAt: "6"}
+	path := filepath.Join(t.TempDir(), "crawl.json")
+
+	var ids []string
+	var progress []CrawlCheckpoint
+	config := &CrawlerConfig{
+		CheckpointPath: path,
+		PageSize:       3,
+		Sink:           collectIDs(&ids),
+		Progress:       func(c CrawlCheckpoint) { progress = append(progress, c) },
+	}
+
+	err := NewCrawler(api, config).Run(context.Background())
+	require.ErrorContains(t, err, "failed to fetch page 3: connection reset")
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, ids)
+	require.Len(t, progress, 2)
+	assert.Equal(t, CrawlCheckpoint{
+		Cursor:    "6",
+		Pages:     2,
+		Events:    6,
+		StartedAt: progress[1].StartedAt,
+		UpdatedAt: progress[1].UpdatedAt,
+	}, progress[1])
+
+	// A new crawler picks up from the persisted cursor
+	require.NoError(t, NewCrawler(api, config).Run(context.Background()))
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, ids)
+	assert.Equal(t, []string{"", "3", "6", "6", "9"}, api.calls)
+
+	last := progress[len(progress)-1]
+	assert.True(t, last.Complete)
+	assert.Equal(t, 4, last.Pages)
+	assert.Equal(t, 10, last.Events)
+	assert.Equal(t, progress[0].StartedAt.Unix(), last.StartedAt.Unix())
+
+	// Temporary files are not left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCrawlerRedeliversPageAfterSinkFailure(t *testing.T) {
+	api := &keysetAPI{total: 4}
+	path := filepath.Join(t.TempDir(), "crawl.json")
+
+	var ids []string
+	failed := false
+	config := &CrawlerConfig{
+		CheckpointPath: path,
+		PageSize:       2,
+		Sink: func(ctx context.Context, events []Event) error {
+			if events[0].ID == "3" && !failed {
+				failed = true
+				return errors.New("disk full")
+			}
+			return collectIDs(&ids)(ctx, events)
+		},
+	}
+
+	err := NewCrawler(api, config).Run(context.Background())
+	require.ErrorContains(t, err, "failed to deliver page 2: disk full")
+
+	require.NoError(t, NewCrawler(api, config).Run(context.Background()))
+	assert.Equal(t, []string{"1", "2", "3", "4"}, ids)
+}
+
+func TestCrawlerActiveOnly(t *testing.T) {
+	api := &keysetAPI{total: 6}
+	path := filepath.Join(t.TempDir(), "crawl.json")
+
+	var ids []string
+	require.NoError(t, NewCrawler(api, &CrawlerConfig{
+		CheckpointPath: path,
+		ActiveOnly:     true,
+		Sink:           collectIDs(&ids),
+	}).Run(context.Background()))
+	assert.Equal(t, []string{"1", "3", "5"}, ids)
+
+	// A completed crawl starts over
+	ids = nil
+	require.NoError(t, NewCrawler(api, &CrawlerConfig{
+		CheckpointPath: path,
+		Sink:           collectIDs(&ids),
+	}).Run(context.Background()))
+	assert.Len(t, ids, 6)
+}
+
+func TestCrawlerRejectsMismatchedCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crawl.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"cursor": "3", "active_only": true}`), 0o644))
+
+	err := NewCrawler(&keysetAPI{}, &CrawlerConfig{
+		CheckpointPath: path,
+		Sink:           func(context.Context, []Event) error { return nil },
+	}).Run(context.Background())
+	assert.ErrorContains(t, err, "was written with active only true")
+
+	err = NewCrawler(&keysetAPI{}, nil).Run(context.Background())
+	assert.ErrorContains(t, err, "crawler has no sink")
+}
@@ -0,0 +1,196 @@
+package polymarket_gamma
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ChangeKind describes how a field changed between two snapshots
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is one field-level difference between two snapshots.
+//
+// Path uses the JSON field names, with nested records addressed by ID rather than
+// position, such as "markets[id=12].bestAsk" or "tags[id=3]". Added and removed
+// records have a nil Old or New respectively.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  any
+	New  any
+}
+
+// Differ compares snapshots, skipping ignored fields
+type Differ struct {
+	// Ignore lists fields to skip. An entry without a dot matches that field at any
+	// depth ("updatedAt", "volume24hr"); a dotted entry matches one path, with "[]"
+	// standing for any record ID ("markets[].bestBid").
+	Ignore []string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Diff returns the changes from old to new
+func Diff(old, new Event) []Change {
+	return (&Differ{}).Diff(old, new)
+}
+
+// DiffMarkets returns the changes from old to new
+func DiffMarkets(old, new Market) []Change {
+	return (&Differ{}).DiffMarkets(old, new)
+}
+
+// Diff returns the changes from old to new
+func (d *Differ) Diff(old, new Event) []Change {
+	var changes []Change
+	d.diffValue("", reflect.ValueOf(old), reflect.ValueOf(new), &changes)
+	return changes
+}
+
+// DiffMarkets returns the changes from old to new
+func (d *Differ) DiffMarkets(old, new Market) []Change {
+	var changes []Change
+	d.diffValue("", reflect.ValueOf(old), reflect.ValueOf(new), &changes)
+	return changes
+}
+
+func (d *Differ) diffValue(path string, old, new reflect.Value, changes *[]Change) {
+	if path != "" && d.ignored(path) {
+		return
+	}
+
+	switch {
+	case old.Type() == timeType:
+		if !old.Interface().(time.Time).Equal(new.Interface().(time.Time)) {
+			*changes = append(*changes, Change{Path: path, Kind: ChangeModified, Old: old.Interface(), New: new.Interface()})
+		}
+
+	case old.Kind() == reflect.Struct:
+		for i := 0; i < old.NumField(); i++ {
+			field := old.Type().Field(i)
+			name := jsonName(field)
+			if !field.IsExported() || name == "" {
+				continue
+			}
+			d.diffValue(joinPath(path, name), old.Field(i), new.Field(i), changes)
+		}
+
+	case old.Kind() == reflect.Pointer:
+		switch {
+		case old.IsNil() && new.IsNil():
+		case old.IsNil() || new.IsNil():
+			*changes = append(*changes, Change{Path: path, Kind: ChangeModified, Old: old.Interface(), New: new.Interface()})
+		default:
+			d.diffValue(path, old.Elem(), new.Elem(), changes)
+		}
+
+	case old.Kind() == reflect.Slice && hasIDField(old.Type().Elem()):
+		d.diffRecords(path, old, new, changes)
+
+	default:
+		if !reflect.DeepEqual(old.Interface(), new.Interface()) {
+			*changes = append(*changes, Change{Path: path, Kind: ChangeModified, Old: old.Interface(), New: new.Interface()})
+		}
+	}
+}
+
+// diffRecords matches the elements of two slices of records by ID, reporting added
+// records in new's order and removed records in old's order
+func (d *Differ) diffRecords(path string, old, new reflect.Value, changes *[]Change) {
+	oldByID := make(map[string]reflect.Value, old.Len())
+	for i := 0; i < old.Len(); i++ {
+		oldByID[recordID(old.Index(i))] = old.Index(i)
+	}
+
+	newIDs := make(map[string]bool, new.Len())
+	for i := 0; i < new.Len(); i++ {
+		id := recordID(new.Index(i))
+		newIDs[id] = true
+
+		recordPath := path + "[id=" + id + "]"
+		previous, ok := oldByID[id]
+		if !ok {
+			if !d.ignored(recordPath) {
+				*changes = append(*changes, Change{Path: recordPath, Kind: ChangeAdded, New: new.Index(i).Interface()})
+			}
+			continue
+		}
+		d.diffValue(recordPath, previous, new.Index(i), changes)
+	}
+
+	for i := 0; i < old.Len(); i++ {
+		id := recordID(old.Index(i))
+		recordPath := path + "[id=" + id + "]"
+		if !newIDs[id] && !d.ignored(recordPath) {
+			*changes = append(*changes, Change{Path: recordPath, Kind: ChangeRemoved, Old: old.Index(i).Interface()})
+		}
+	}
+}
+
+func (d *Differ) ignored(path string) bool {
+	if len(d.Ignore) == 0 {
+		return false
+	}
+
+	pattern := genericPath(path)
+	name := pattern[strings.LastIndex(pattern, ".")+1:]
+	for _, ignore := range d.Ignore {
+		if ignore == pattern || (!strings.Contains(ignore, ".") && ignore == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// genericPath replaces record IDs in path with "[]"
+func genericPath(path string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(path, "[id=")
+		if start < 0 {
+			b.WriteString(path)
+			return b.String()
+		}
+		end := strings.Index(path[start:], "]")
+		b.WriteString(path[:start])
+		b.WriteString("[]")
+		path = path[start+end+1:]
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+func hasIDField(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	field, ok := t.FieldByName("ID")
+	return ok && field.Type.Kind() == reflect.String
+}
+
+func recordID(v reflect.Value) string {
+	return v.FieldByName("ID").String()
+}
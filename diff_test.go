@@ -0,0 +1,65 @@
+package polymarket_gamma
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffNestedRecordsByID(t *testing.T) {
+	old := mockEvent("1")
+	old.Markets = []Market{mockMarket("10"), mockMarket("11")}
+
+	new := old
+	new.Title = "Renamed"
+	new.EndDate = old.EndDate.Add(time.Hour)
+	new.Markets = []Market{mockMarket("12"), old.Markets[1]}
+	new.Markets[1].BestAsk = 0.7
+	new.Markets[1].Closed = true
+	new.Tags = append([]Tag{{ID: "tag-2", Label: "New"}}, old.Tags...)
+
+	changes := Diff(old, new)
+	assert.Equal(t, []Change{
+		{Path: "title", Kind: ChangeModified, Old: "Test Event", New: "Renamed"},
+		{Path: "endDate", Kind: ChangeModified, Old: old.EndDate, New: new.EndDate},
+		{Path: "markets[id=12]", Kind: ChangeAdded, New: new.Markets[0]},
+		{Path: "markets[id=11].closed", Kind: ChangeModified, Old: false, New: true},
+		{Path: "markets[id=11].bestAsk", Kind: ChangeModified, Old: old.Markets[1].BestAsk, New: 0.7},
+		{Path: "markets[id=10]", Kind: ChangeRemoved, Old: old.Markets[0]},
+		{Path: "tags[id=tag-2]", Kind: ChangeAdded, New: new.Tags[0]},
+	}, changes)
+}
+
+func TestDiffIgnore(t *testing.T) {
+	old := mockEvent("1")
+	new := old
+	new.UpdatedAt = time.Now()
+	new.Volume24hr = 1
+	new.Volume = 2
+	new.Markets = []Market{old.Markets[0]}
+	new.Markets[0].UpdatedAt = time.Now()
+	new.Markets[0].BestBid = 0.4
+	new.Markets[0].BestAsk = 0.6
+
+	differ := &Differ{Ignore: []string{"updatedAt", "volume24hr", "markets[].bestBid"}}
+	assert.Equal(t, []Change{
+		{Path: "volume", Kind: ChangeModified, Old: old.Volume, New: 2.0},
+		{Path: "markets[id=market-1].bestAsk", Kind: ChangeModified, Old: old.Markets[0].BestAsk, New: 0.6},
+	}, differ.Diff(old, new))
+
+	assert.Empty(t, Diff(old, old))
+}
+
+func TestDiffMarketsAndPointers(t *testing.T) {
+	old := mockMarket("1")
+	new := old
+	new.ImageOptimized = &ImageOptimization{ID: "img"}
+	new.Outcomes = `["Yes","No","Maybe"]`
+
+	changes := DiffMarkets(old, new)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "outcomes", changes[0].Path)
+	assert.Equal(t, "imageOptimized", changes[1].Path)
+	assert.Nil(t, changes[1].Old.(*ImageOptimization))
+}
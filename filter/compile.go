@@ -0,0 +1,635 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CalderWhite/polymarket-gamma-go/internal/fields"
+)
+
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindString
+	kindBool
+	kindTime
+	kindDuration
+	kindList
+	kindRecord
+	kindNull
+)
+
+func (k kind) String() string {
+	return [...]string{"number", "string", "boolean", "time", "duration", "list", "record", "null"}[k]
+}
+
+// typ is the static type of an expression. of is the struct type of a record, or the
+// element type of a list.
+type typ struct {
+	kind kind
+	of   reflect.Type
+}
+
+// env is the state an expression is evaluated in
+type env struct {
+	root    reflect.Value
+	element reflect.Value
+	now     time.Time
+}
+
+// eval evaluates a compiled expression. Values are float64, string, bool, time.Time,
+// time.Duration, or a reflect.Value for lists and records; nil stands for a missing
+// record or a value that could not be converted.
+type eval func(e *env) any
+
+// scope is the static counterpart of env
+type scope struct {
+	root    reflect.Type
+	element reflect.Type
+}
+
+func compileNode(n node, s scope) (eval, typ, error) {
+	switch n := n.(type) {
+	case *literal:
+		return compileLiteral(n)
+	case *field:
+		return compileField(n.pos, n.name, s.root, func(e *env) reflect.Value { return e.root })
+	case *element:
+		if n.name == "" {
+			t, ok := typeOf(s.element)
+			if !ok {
+				return nil, typ{}, &Error{Pos: n.pos, Msg: fmt.Sprintf("elements of type %s cannot be used in a filter", s.element)}
+			}
+			return func(e *env) any { return convert(e.element, t) }, t, nil
+		}
+		if s.element.Kind() != reflect.Struct {
+			return nil, typ{}, &Error{Pos: n.pos, Msg: fmt.Sprintf("%s elements have no field %q", s.element, n.name)}
+		}
+		return compileField(n.pos, n.name, s.element, func(e *env) reflect.Value { return e.element })
+	case *access:
+		x, xt, err := compileNode(n.x, s)
+		if err != nil {
+			return nil, typ{}, err
+		}
+		if xt.kind != kindRecord {
+			return nil, typ{}, &Error{Pos: n.pos, Msg: fmt.Sprintf("cannot read field %q of a %s", n.name, xt.kind)}
+		}
+		return compileField(n.pos, n.name, xt.of, func(e *env) reflect.Value {
+			v, _ := x(e).(reflect.Value)
+			return v
+		})
+	case *call:
+		return compileCall(n, s)
+	case *unary:
+		return compileUnary(n, s)
+	case *binary:
+		return compileBinary(n, s)
+	}
+	panic(fmt.Sprintf("filter: unexpected node %T", n))
+}
+
+func compileLiteral(n *literal) (eval, typ, error) {
+	value := n.value
+	var t typ
+	switch value.(type) {
+	case float64:
+		t = typ{kind: kindNumber}
+	case string:
+		t = typ{kind: kindString}
+	case bool:
+		t = typ{kind: kindBool}
+	case time.Duration:
+		t = typ{kind: kindDuration}
+	default:
+		t = typ{kind: kindNull}
+	}
+	return func(*env) any { return value }, t, nil
+}
+
+// compileField reads name from the record of type structType that base returns
+func compileField(pos int, name string, structType reflect.Type, base func(*env) reflect.Value) (eval, typ, error) {
+	f, err := fields.Lookup(structType, name)
+	if err != nil {
+		return nil, typ{}, &Error{Pos: pos, Msg: err.Error()}
+	}
+	t, ok := typeOf(f.Type)
+	if !ok {
+		return nil, typ{}, &Error{Pos: pos, Msg: fmt.Sprintf("field %q cannot be used in a filter", f.Name)}
+	}
+
+	return func(e *env) any {
+		record := base(e)
+		if !record.IsValid() {
+			return nil
+		}
+		return convert(f.Get(record), t)
+	}, t, nil
+}
+
+// typeOf maps a Go type to the filter type its values have
+func typeOf(t reflect.Type) (typ, bool) {
+	if fields.IsTime(t) {
+		return typ{kind: kindTime}, true
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return typ{kind: kindBool}, true
+	case reflect.String:
+		return typ{kind: kindString}, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return typ{kind: kindNumber}, true
+	case reflect.Slice:
+		return typ{kind: kindList, of: t.Elem()}, true
+	case reflect.Struct:
+		return typ{kind: kindRecord, of: t}, true
+	case reflect.Pointer:
+		if t.Elem().Kind() == reflect.Struct {
+			return typ{kind: kindRecord, of: t.Elem()}, true
+		}
+	}
+	return typ{}, false
+}
+
+// convert reads v as a value of type t
+func convert(v reflect.Value, t typ) any {
+	switch t.kind {
+	case kindTime:
+		return v.Interface().(time.Time)
+	case kindBool:
+		return v.Bool()
+	case kindString:
+		return v.String()
+	case kindNumber:
+		n, _ := fields.Number(v.Interface())
+		return n
+	case kindRecord:
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil
+			}
+			return v.Elem()
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func compileCall(n *call, s scope) (eval, typ, error) {
+	arity := func(want ...int) error {
+		for _, w := range want {
+			if len(n.args) == w {
+				return nil
+			}
+		}
+		return &Error{Pos: n.pos, Msg: fmt.Sprintf("%s takes %s argument(s), not %d", n.name, joinInts(want), len(n.args))}
+	}
+
+	switch n.name {
+	case "now":
+		if err := arity(0); err != nil {
+			return nil, typ{}, err
+		}
+		return func(e *env) any { return e.now }, typ{kind: kindTime}, nil
+
+	case "date":
+		if err := arity(1); err != nil {
+			return nil, typ{}, err
+		}
+		lit, ok := n.args[0].(*literal)
+		text, isString := lit.valueString()
+		if !ok || !isString {
+			return nil, typ{}, &Error{Pos: n.args[0].position(), Msg: "date takes a string literal"}
+		}
+		t, err := parseDate(text)
+		if err != nil {
+			return nil, typ{}, &Error{Pos: n.args[0].position(), Msg: err.Error()}
+		}
+		return func(*env) any { return t }, typ{kind: kindTime}, nil
+
+	case "any", "all", "count":
+		if n.name == "count" {
+			if err := arity(1, 2); err != nil {
+				return nil, typ{}, err
+			}
+		} else if err := arity(2); err != nil {
+			return nil, typ{}, err
+		}
+
+		list, lt, err := compileNode(n.args[0], s)
+		if err != nil {
+			return nil, typ{}, err
+		}
+		if lt.kind != kindList {
+			return nil, typ{}, &Error{Pos: n.args[0].position(), Msg: fmt.Sprintf("%s needs a list, not a %s", n.name, lt.kind)}
+		}
+
+		var predicate eval
+		if len(n.args) == 2 {
+			var pt typ
+			predicate, pt, err = compileNode(n.args[1], scope{root: s.root, element: lt.of})
+			if err != nil {
+				return nil, typ{}, err
+			}
+			if pt.kind != kindBool {
+				return nil, typ{}, &Error{Pos: n.args[1].position(), Msg: fmt.Sprintf("%s needs a boolean condition, not a %s", n.name, pt.kind)}
+			}
+		}
+		return compileQuantifier(n.name, list, predicate), quantifierType(n.name), nil
+
+	case "len":
+		if err := arity(1); err != nil {
+			return nil, typ{}, err
+		}
+		x, xt, err := compileNode(n.args[0], s)
+		if err != nil {
+			return nil, typ{}, err
+		}
+		switch xt.kind {
+		case kindString:
+			return func(e *env) any {
+				s, ok := x(e).(string)
+				if !ok {
+					return nil
+				}
+				return float64(len(s))
+			}, typ{kind: kindNumber}, nil
+		case kindList:
+			return func(e *env) any {
+				list, _ := x(e).(reflect.Value)
+				if !list.IsValid() {
+					return nil
+				}
+				return float64(list.Len())
+			}, typ{kind: kindNumber}, nil
+		}
+		return nil, typ{}, &Error{Pos: n.args[0].position(), Msg: fmt.Sprintf("len needs a string or list, not a %s", xt.kind)}
+
+	case "lower":
+		if err := arity(1); err != nil {
+			return nil, typ{}, err
+		}
+		x, err := compileTyped(n.args[0], s, kindString, "lower")
+		if err != nil {
+			return nil, typ{}, err
+		}
+		return func(e *env) any {
+			s, ok := x(e).(string)
+			if !ok {
+				return nil
+			}
+			return strings.ToLower(s)
+		}, typ{kind: kindString}, nil
+
+	case "contains":
+		if err := arity(2); err != nil {
+			return nil, typ{}, err
+		}
+		x, xt, err := compileNode(n.args[0], s)
+		if err != nil {
+			return nil, typ{}, err
+		}
+		sub, err := compileTyped(n.args[1], s, kindString, "contains")
+		if err != nil {
+			return nil, typ{}, err
+		}
+		switch {
+		case xt.kind == kindString:
+			return func(e *env) any {
+				s, ok := x(e).(string)
+				want, wantOK := sub(e).(string)
+				return ok && wantOK && strings.Contains(s, want)
+			}, typ{kind: kindBool}, nil
+		case xt.kind == kindList && xt.of.Kind() == reflect.String:
+			return func(e *env) any {
+				list, _ := x(e).(reflect.Value)
+				want, ok := sub(e).(string)
+				for i := 0; ok && list.IsValid() && i < list.Len(); i++ {
+					if list.Index(i).String() == want {
+						return true
+					}
+				}
+				return false
+			}, typ{kind: kindBool}, nil
+		}
+		return nil, typ{}, &Error{Pos: n.args[0].position(), Msg: fmt.Sprintf("contains needs a string or list of strings, not a %s", xt.kind)}
+
+	case "matches":
+		if err := arity(2); err != nil {
+			return nil, typ{}, err
+		}
+		x, err := compileTyped(n.args[0], s, kindString, "matches")
+		if err != nil {
+			return nil, typ{}, err
+		}
+		lit, ok := n.args[1].(*literal)
+		pattern, isString := lit.valueString()
+		if !ok || !isString {
+			return nil, typ{}, &Error{Pos: n.args[1].position(), Msg: "matches takes a regular expression string literal"}
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, typ{}, &Error{Pos: n.args[1].position(), Msg: fmt.Sprintf("invalid regular expression: %v", err)}
+		}
+		return func(e *env) any {
+			s, ok := x(e).(string)
+			return ok && re.MatchString(s)
+		}, typ{kind: kindBool}, nil
+	}
+
+	return nil, typ{}, &Error{Pos: n.pos, Msg: fmt.Sprintf("unknown function %q", n.name)}
+}
+
+func (n *literal) valueString() (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	s, ok := n.value.(string)
+	return s, ok
+}
+
+func compileQuantifier(name string, list, predicate eval) eval {
+	return func(e *env) any {
+		v, _ := list(e).(reflect.Value)
+		count := 0
+		for i := 0; v.IsValid() && i < v.Len(); i++ {
+			if predicate != nil && predicate(&env{root: e.root, element: v.Index(i), now: e.now}) != true {
+				if name == "all" {
+					return false
+				}
+				continue
+			}
+			if name == "any" {
+				return true
+			}
+			count++
+		}
+
+		switch name {
+		case "any":
+			return false
+		case "all":
+			return true
+		}
+		return float64(count)
+	}
+}
+
+func quantifierType(name string) typ {
+	if name == "count" {
+		return typ{kind: kindNumber}
+	}
+	return typ{kind: kindBool}
+}
+
+// compileTyped compiles n and checks it has kind want
+func compileTyped(n node, s scope, want kind, context string) (eval, error) {
+	x, xt, err := compileNode(n, s)
+	if err != nil {
+		return nil, err
+	}
+	if xt.kind != want {
+		return nil, &Error{Pos: n.position(), Msg: fmt.Sprintf("%s needs a %s, not a %s", context, want, xt.kind)}
+	}
+	return x, nil
+}
+
+func compileUnary(n *unary, s scope) (eval, typ, error) {
+	x, xt, err := compileNode(n.x, s)
+	if err != nil {
+		return nil, typ{}, err
+	}
+
+	switch {
+	case n.op == "!" && xt.kind == kindBool:
+		return func(e *env) any { return x(e) != true }, xt, nil
+	case n.op == "-" && xt.kind == kindNumber:
+		return func(e *env) any {
+			if v, ok := x(e).(float64); ok {
+				return -v
+			}
+			return nil
+		}, xt, nil
+	case n.op == "-" && xt.kind == kindDuration:
+		return func(e *env) any {
+			if v, ok := x(e).(time.Duration); ok {
+				return -v
+			}
+			return nil
+		}, xt, nil
+	}
+	return nil, typ{}, &Error{Pos: n.pos, Msg: fmt.Sprintf("%s does not apply to a %s", n.op, xt.kind)}
+}
+
+func compileBinary(n *binary, s scope) (eval, typ, error) {
+	x, xt, err := compileNode(n.x, s)
+	if err != nil {
+		return nil, typ{}, err
+	}
+	y, yt, err := compileNode(n.y, s)
+	if err != nil {
+		return nil, typ{}, err
+	}
+
+	mismatch := &Error{Pos: n.pos, Msg: fmt.Sprintf("%s does not apply to a %s and a %s", n.op, xt.kind, yt.kind)}
+
+	switch n.op {
+	case "&&", "||":
+		if xt.kind != kindBool || yt.kind != kindBool {
+			return nil, typ{}, mismatch
+		}
+		if n.op == "&&" {
+			return func(e *env) any { return x(e) == true && y(e) == true }, xt, nil
+		}
+		return func(e *env) any { return x(e) == true || y(e) == true }, xt, nil
+
+	case "+", "-", "*", "/":
+		op, t, ok := arithmetic(n.op, xt.kind, yt.kind)
+		if !ok {
+			return nil, typ{}, mismatch
+		}
+		return func(e *env) any {
+			a, b := x(e), y(e)
+			if a == nil || b == nil {
+				return nil
+			}
+			return op(a, b)
+		}, typ{kind: t}, nil
+	}
+
+	// Comparisons. A string compared with a number is read as a number, since the API
+	// returns some amounts as decimal strings.
+	if xt.kind == kindString && yt.kind == kindNumber {
+		x, xt = numeric(x), yt
+	} else if xt.kind == kindNumber && yt.kind == kindString {
+		y, yt = numeric(y), xt
+	}
+
+	equality := n.op == "==" || n.op == "!="
+	switch {
+	case xt.kind == kindNull || yt.kind == kindNull:
+		if !equality || (xt.kind != kindRecord && yt.kind != kindRecord && xt.kind != yt.kind) {
+			return nil, typ{}, mismatch
+		}
+	case xt.kind != yt.kind:
+		return nil, typ{}, mismatch
+	case !equality && xt.kind != kindNumber && xt.kind != kindString && xt.kind != kindTime && xt.kind != kindDuration:
+		return nil, typ{}, mismatch
+	case equality && (xt.kind == kindList || xt.kind == kindRecord):
+		return nil, typ{}, mismatch
+	}
+
+	op := n.op
+	return func(e *env) any {
+		a, b := x(e), y(e)
+		if a == nil || b == nil {
+			switch op {
+			case "==":
+				return isNull(a) && isNull(b)
+			case "!=":
+				return isNull(a) != isNull(b)
+			}
+			return false
+		}
+
+		order := compare(a, b)
+		switch op {
+		case "==":
+			return order == 0
+		case "!=":
+			return order != 0
+		case "<":
+			return order < 0
+		case "<=":
+			return order <= 0
+		case ">":
+			return order > 0
+		default:
+			return order >= 0
+		}
+	}, typ{kind: kindBool}, nil
+}
+
+// isNull reports whether v is null or a missing record
+func isNull(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv, ok := v.(reflect.Value)
+	return ok && !rv.IsValid()
+}
+
+// numeric parses a string expression as a number, yielding nil when it isn't one
+func numeric(x eval) eval {
+	return func(e *env) any {
+		s, ok := x(e).(string)
+		if !ok {
+			return nil
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil
+		}
+		return n
+	}
+}
+
+func arithmetic(op string, x, y kind) (func(a, b any) any, kind, bool) {
+	switch {
+	case x == kindNumber && y == kindNumber:
+		return func(a, b any) any {
+			p, q := a.(float64), b.(float64)
+			switch op {
+			case "+":
+				return p + q
+			case "-":
+				return p - q
+			case "*":
+				return p * q
+			}
+			if q == 0 {
+				return nil
+			}
+			return p / q
+		}, kindNumber, true
+
+	case op == "+" && x == kindString && y == kindString:
+		return func(a, b any) any { return a.(string) + b.(string) }, kindString, true
+
+	case (op == "+" || op == "-") && x == kindTime && y == kindDuration:
+		return func(a, b any) any {
+			if op == "-" {
+				return a.(time.Time).Add(-b.(time.Duration))
+			}
+			return a.(time.Time).Add(b.(time.Duration))
+		}, kindTime, true
+
+	case op == "+" && x == kindDuration && y == kindTime:
+		return func(a, b any) any { return b.(time.Time).Add(a.(time.Duration)) }, kindTime, true
+
+	case op == "-" && x == kindTime && y == kindTime:
+		return func(a, b any) any { return a.(time.Time).Sub(b.(time.Time)) }, kindDuration, true
+
+	case (op == "+" || op == "-") && x == kindDuration && y == kindDuration:
+		return func(a, b any) any {
+			if op == "-" {
+				return a.(time.Duration) - b.(time.Duration)
+			}
+			return a.(time.Duration) + b.(time.Duration)
+		}, kindDuration, true
+	}
+	return nil, 0, false
+}
+
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case float64:
+		return cmp3(a < b.(float64), a > b.(float64))
+	case string:
+		return strings.Compare(a, b.(string))
+	case bool:
+		if a == b.(bool) {
+			return 0
+		}
+		return 1
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case time.Duration:
+		return cmp3(a < b.(time.Duration), a > b.(time.Duration))
+	}
+	return 1
+}
+
+func cmp3(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+func parseDate(text string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, want RFC 3339 or YYYY-MM-DD", text)
+}
+
+func joinInts(values []int) string {
+	var parts []string
+	for _, v := range values {
+		parts = append(parts, strconv.Itoa(v))
+	}
+	return strings.Join(parts, " or ")
+}
@@ -0,0 +1,128 @@
+// Package filter compiles small boolean expressions evaluated client-side against
+// events and markets, for conditions the API cannot filter on.
+//
+//	category == "Sports" && any(markets, .spread < 0.02) && endDate < now()+24h
+//
+// Bare names are fields of the value being filtered, by JSON or Go name. Inside
+// any, all and count, ".name" is a field of the current list element and "." the
+// element itself. Unknown fields are compile errors, with a suggestion when the
+// name looks like a typo.
+//
+// Operators, loosest first: ||, &&, comparisons (== != < <= > >=), + -, * /, and
+// unary ! and -. Literals are numbers, "strings" or 'strings', true, false, null and
+// durations such as 90s, 15m, 24h, 7d or 2w. Times and durations add and subtract
+// as in Go. A string compared with a number is read as a number, so Market.Volume,
+// which the API returns as a string, compares with 1000.
+//
+// Functions:
+//
+//	now()                  the time the value is matched
+//	date("2026-11-01")     a time, RFC 3339 or YYYY-MM-DD
+//	any(list, condition)   whether any element satisfies condition
+//	all(list, condition)   whether every element satisfies condition
+//	count(list)            the number of elements
+//	count(list, condition) the number of elements satisfying condition
+//	len(string or list)    the length
+//	lower(string)          the string in lower case
+//	contains(s, sub)       whether string s contains sub, or list s has element sub
+//	matches(s, "regexp")   whether s matches the regular expression
+//
+// A missing record, such as a nil imageOptimized, equals null. Comparisons involving
+// it are otherwise false.
+package filter
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+// Error is a compile error
+type Error struct {
+	// Pos is the 1-based column of the offending token in the filter
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+// Filter is a compiled expression over events or markets. It is safe for concurrent
+// use.
+type Filter[T polymarket_gamma.Event | polymarket_gamma.Market] struct {
+	source string
+	eval   eval
+}
+
+// Compile compiles an expression over events
+func Compile(source string) (*Filter[polymarket_gamma.Event], error) {
+	return compile[polymarket_gamma.Event](source)
+}
+
+// CompileMarket compiles an expression over markets
+func CompileMarket(source string) (*Filter[polymarket_gamma.Market], error) {
+	return compile[polymarket_gamma.Market](source)
+}
+
+func compile[T polymarket_gamma.Event | polymarket_gamma.Market](source string) (*Filter[T], error) {
+	n, err := parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile filter: %w", err)
+	}
+
+	root := reflect.TypeFor[T]()
+	e, t, err := compileNode(n, scope{root: root, element: root})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile filter: %w", err)
+	}
+	if t.kind != kindBool {
+		return nil, fmt.Errorf("failed to compile filter: %w", &Error{Pos: n.position(), Msg: fmt.Sprintf("filter must be a boolean, not a %s", t.kind)})
+	}
+	return &Filter[T]{source: source, eval: e}, nil
+}
+
+// String returns the source the filter was compiled from
+func (f *Filter[T]) String() string {
+	return f.source
+}
+
+// Match reports whether v satisfies the filter
+func (f *Filter[T]) Match(v T) bool {
+	return f.MatchAt(v, time.Now())
+}
+
+// MatchAt reports whether v satisfies the filter with now() returning now
+func (f *Filter[T]) MatchAt(v T, now time.Time) bool {
+	root := reflect.ValueOf(v)
+	return f.eval(&env{root: root, element: root, now: now}) == true
+}
+
+// Apply returns the values of seq that satisfy the filter, such as the events from
+// Pager.Events. Errors are passed through.
+func (f *Filter[T]) Apply(seq iter.Seq2[T, error]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v, err := range seq {
+			if err != nil || f.Match(v) {
+				if !yield(v, err) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Select returns the values that satisfy the filter
+func (f *Filter[T]) Select(values []T) []T {
+	now := time.Now()
+	var selected []T
+	for _, v := range values {
+		if f.MatchAt(v, now) {
+			selected = append(selected, v)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,127 @@
+package filter
+
+import (
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func testEvent() polymarket_gamma.Event {
+	return polymarket_gamma.Event{
+		ID:         "1",
+		Title:      "Lakers vs Celtics",
+		Category:   "Sports",
+		EndDate:    now.Add(6 * time.Hour),
+		Volume24hr: 250_000,
+		Tags:       []polymarket_gamma.Tag{{ID: "t1", Slug: "nba"}},
+		SubEvents:  []string{"q1", "q2"},
+		Markets: []polymarket_gamma.Market{
+			{ID: "m1", Spread: 0.01, Volume: "1200.5", Closed: false},
+			{ID: "m2", Spread: 0.08, Volume: "10", Closed: true},
+		},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	event := testEvent()
+
+	for source, want := range map[string]bool{
+		`category == "Sports" && any(markets, .spread < 0.02) && endDate < now()+24h`: true,
+		`category == "Sports" && all(markets, .spread < 0.02)`:                        false,
+		`count(markets, !.closed) == 1 && count(markets) == 2`:                        true,
+		`any(markets, .volume > 1000)`:                                                true,
+		`any(tags, .slug == 'nba') && contains(subEvents, "q2")`:                      true,
+		`Volume24hr >= 250_000 && volume24hr < 1e6`:                                   true,
+		`endDate - now() > 7d || endDate > date("2026-10-19")`:                        false,
+		`lower(title) == "lakers vs celtics" && matches(title, "^Lakers")`:            true,
+		`contains(title, "Celtics") && len(markets) * 2 == 4`:                         true,
+		`imageOptimized == null && !(imageOptimized.id == "x")`:                       true,
+		`any(markets, .spread > 0.05 && category == "Sports")`:                        true,
+		`-volume24hr < -(1000) && startDate < now() - 1w`:                             true,
+	} {
+		f, err := Compile(source)
+		require.NoError(t, err, source)
+		assert.Equal(t, want, f.MatchAt(event, now), source)
+	}
+
+	market, err := CompileMarket(`volume >= 1000 && spread <= 0.01`)
+	require.NoError(t, err)
+	assert.Equal(t, []polymarket_gamma.Market{event.Markets[0]}, market.Select(event.Markets))
+}
+
+func TestCompileErrors(t *testing.T) {
+	for source, want := range map[string]string{
+		`categry == "Sports"`:           `column 1: unknown field "categry" on Event (did you mean "category"?)`,
+		`any(markets, .sprad < 0.02)`:   `column 14: unknown field "sprad" on Market (did you mean "spread"?)`,
+		`imageOptimized.idd == ""`:      `column 15: unknown field "idd" on ImageOptimization (did you mean "id"?)`,
+		`category == 1 && volume`:       `column 15: && does not apply to a boolean and a number`,
+		`category > true`:               `column 10: > does not apply to a string and a boolean`,
+		`volume`:                        `column 1: filter must be a boolean, not a number`,
+		`any(title, . == "x")`:          `column 5: any needs a list, not a string`,
+		`any(markets, .spread)`:         `column 14: any needs a boolean condition, not a number`,
+		`soon() > now()`:                `column 1: unknown function "soon"`,
+		`endDate < now() + 3y`:          `column 20: unknown duration unit "y"`,
+		`category == "Sports`:           `column 13: unterminated string`,
+		`(category == "Sports"`:         `column 22: expected ")", found end of filter`,
+		`matches(title, "(")`:           "column 16: invalid regular expression: error parsing regexp: missing closing ): `(`",
+		`endDate < date("tomorrow")`:    `column 16: invalid date "tomorrow", want RFC 3339 or YYYY-MM-DD`,
+		`category == "Sports" category`: `column 22: unexpected "category"`,
+		`any(subEvents, .id == "x")`:    `column 16: string elements have no field "id"`,
+	} {
+		_, err := Compile(source)
+		var compileErr *Error
+		require.True(t, errors.As(err, &compileErr), source)
+		assert.EqualError(t, compileErr, want, source)
+		assert.ErrorContains(t, err, "failed to compile filter: ")
+	}
+}
+
+func TestLexUTF8Whitespace(t *testing.T) {
+	_, err := Compile("category\u00a0== \"Sports\"")
+	require.NoError(t, err)
+
+	for _, source := range []string{"category\xa0== \"Sports\"", "category\x85== \"Sports\""} {
+		_, err := Compile(source)
+		var compileErr *Error
+		require.True(t, errors.As(err, &compileErr), source)
+		assert.Equal(t, 9, compileErr.Pos, source)
+		assert.Contains(t, compileErr.Msg, "unexpected character", source)
+	}
+}
+
+func TestApply(t *testing.T) {
+	events := func(yield func(polymarket_gamma.Event, error) bool) {
+		for _, id := range []string{"1", "2", "3"} {
+			event := testEvent()
+			event.ID = id
+			event.Featured = id != "2"
+			if !yield(event, nil) {
+				return
+			}
+		}
+		yield(polymarket_gamma.Event{}, errors.New("page failed"))
+	}
+
+	f, err := Compile("featured")
+	require.NoError(t, err)
+
+	var ids []string
+	var errs []error
+	for event, err := range f.Apply(iter.Seq2[polymarket_gamma.Event, error](events)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"1", "3"}, ids)
+	assert.Equal(t, []error{errors.New("page failed")}, errs)
+	assert.Equal(t, "featured", f.String())
+}
@@ -0,0 +1,365 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDuration
+	tokenString
+	tokenIdent
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+// punctuation lists the operators, longest first so "<=" wins over "<"
+var punctuation = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "(", ")", ",", "."}
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '_' || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') && i+1 < len(src) && (isDigit(src[i+1]) || src[i+1] == '-' || src[i+1] == '+') {
+				i += 2
+				for i < len(src) && isDigit(src[i]) {
+					i++
+				}
+			}
+			number, err := strconv.ParseFloat(strings.ReplaceAll(src[start:i], "_", ""), 64)
+			if err != nil {
+				return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("invalid number %q", src[start:i])}
+			}
+
+			unitStart := i
+			for i < len(src) && isLetter(src[i]) {
+				i++
+			}
+			if unitStart == i {
+				tokens = append(tokens, token{kind: tokenNumber, text: src[start:i], value: number, pos: start + 1})
+				continue
+			}
+			unit, ok := durationUnits[src[unitStart:i]]
+			if !ok {
+				return nil, &Error{Pos: unitStart + 1, Msg: fmt.Sprintf("unknown duration unit %q", src[unitStart:i])}
+			}
+			tokens = append(tokens, token{kind: tokenDuration, text: src[start:i], value: time.Duration(number * float64(unit)), pos: start + 1})
+
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(src) && rune(src[i]) != c {
+				if src[i] == '\\' && c == '"' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return nil, &Error{Pos: start + 1, Msg: "unterminated string"}
+			}
+			i++
+
+			text := src[start+1 : i-1]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(src[start:i])
+				if err != nil {
+					return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("invalid string %s", src[start:i])}
+				}
+				text = unquoted
+			}
+			tokens = append(tokens, token{kind: tokenString, text: src[start:i], value: text, pos: start + 1})
+
+		case isLetter(src[i]):
+			start := i
+			for i < len(src) && (isLetter(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[start:i], pos: start + 1})
+
+		default:
+			matched := false
+			for _, p := range punctuation {
+				if strings.HasPrefix(src[i:], p) {
+					tokens = append(tokens, token{kind: tokenPunct, text: p, pos: i + 1})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &Error{Pos: i + 1, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src) + 1}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// node is a parsed expression
+type node interface {
+	position() int
+}
+
+type (
+	// literal is a number, string, boolean, duration or null
+	literal struct {
+		pos   int
+		value any
+	}
+	// field is a bare name, read from the value being filtered
+	field struct {
+		pos  int
+		name string
+	}
+	// element is ".name", read from the current list element inside any, all and
+	// count, or "." for the element itself
+	element struct {
+		pos  int
+		name string
+	}
+	access struct {
+		pos  int
+		x    node
+		name string
+	}
+	call struct {
+		pos  int
+		name string
+		args []node
+	}
+	unary struct {
+		pos int
+		op  string
+		x   node
+	}
+	binary struct {
+		pos  int
+		op   string
+		x, y node
+	}
+)
+
+func (n *literal) position() int { return n.pos }
+func (n *field) position() int   { return n.pos }
+func (n *element) position() int { return n.pos }
+func (n *access) position() int  { return n.pos }
+func (n *call) position() int    { return n.pos }
+func (n *unary) position() int   { return n.pos }
+func (n *binary) position() int  { return n.pos }
+
+// precedence of the binary operators; higher binds tighter
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5,
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.expression(1)
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &Error{Pos: next.pos, Msg: fmt.Sprintf("unexpected %s", describe(next))}
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) isPunct(text string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isPunct(text) {
+		t := p.peek()
+		return &Error{Pos: t.pos, Msg: fmt.Sprintf("expected %q, found %s", text, describe(t))}
+	}
+	p.next()
+	return nil
+}
+
+// expression parses binary operators of at least the given precedence
+func (p *parser) expression(minPrecedence int) (node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.text]
+		if t.kind != tokenPunct || !ok || prec < minPrecedence {
+			return x, nil
+		}
+		p.next()
+
+		y, err := p.expression(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binary{pos: t.pos, op: t.text, x: x, y: y}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if p.isPunct("!") || p.isPunct("-") {
+		t := p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{pos: t.pos, op: t.text, x: x}, nil
+	}
+	return p.postfix()
+}
+
+func (p *parser) postfix() (node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isPunct(".") {
+		dot := p.next()
+		name := p.next()
+		if name.kind != tokenIdent {
+			return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("expected a field name after %q, found %s", ".", describe(name))}
+		}
+		x = &access{pos: dot.pos, x: x, name: name.text}
+	}
+	return x, nil
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber, tokenDuration, tokenString:
+		return &literal{pos: t.pos, value: t.value}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literal{pos: t.pos, value: true}, nil
+		case "false":
+			return &literal{pos: t.pos, value: false}, nil
+		case "null":
+			return &literal{pos: t.pos, value: nil}, nil
+		}
+
+		if !p.isPunct("(") {
+			return &field{pos: t.pos, name: t.text}, nil
+		}
+		p.next()
+
+		c := &call{pos: t.pos, name: t.text}
+		for !p.isPunct(")") {
+			if len(c.args) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.expression(1)
+			if err != nil {
+				return nil, err
+			}
+			c.args = append(c.args, arg)
+		}
+		p.next()
+		return c, nil
+
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			x, err := p.expression(1)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case ".":
+			if p.peek().kind == tokenIdent {
+				return &element{pos: t.pos, name: p.next().text}, nil
+			}
+			return &element{pos: t.pos}, nil
+		}
+	}
+
+	return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", describe(t))}
+}
+
+func describe(t token) string {
+	if t.kind == tokenEOF {
+		return "end of filter"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
@@ -0,0 +1,379 @@
+package gammatest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response is a fully buffered HTTP response that faults can rewrite. Body holds the
+// bytes as sent on the wire, so it is gzip-compressed when Header says so.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Delay holds back the body (but not the headers) for this long
+	Delay time.Duration
+}
+
+// Fault simulates one kind of misbehaviour. It receives the request and a next
+// function that produces the genuine response; it may skip calling next to answer
+// on its own, or rewrite what next returns.
+type Fault func(req *http.Request, next func() (*Response, error)) (*Response, error)
+
+// Rule decides when a Fault is applied
+type Rule struct {
+	// Path restricts the rule to one request path, e.g. "/events/keyset" (optional)
+	Path string
+	// Match further restricts which requests the rule applies to (optional)
+	Match func(req *http.Request) bool
+	// Probability of firing for a matching request; zero means always
+	Probability float64
+	// After lets the first After matching requests through untouched
+	After int
+	// Times caps how often the rule fires; zero means no limit
+	Times int
+	Fault Fault
+}
+
+// Injector applies rules to requests. The first rule that fires for a request
+// wins. Randomness comes from a seeded RNG, so runs are reproducible.
+type Injector struct {
+	mu    sync.Mutex
+	rng   *rand.Rand
+	rules []*ruleState
+	fired []string
+}
+
+type ruleState struct {
+	Rule
+	index   int
+	matched int
+	fired   int
+}
+
+func NewInjector(seed int64, rules ...Rule) *Injector {
+	injector := &Injector{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+	for i, rule := range rules {
+		injector.rules = append(injector.rules, &ruleState{Rule: rule, index: i})
+	}
+	return injector
+}
+
+// Fired returns "<rule index> <method> <request URI>" for every fault applied so far
+func (i *Injector) Fired() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]string(nil), i.fired...)
+}
+
+// pick returns the fault to apply to req, if any
+func (i *Injector) pick(req *http.Request) Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, rule := range i.rules {
+		if rule.Path != "" && rule.Path != req.URL.Path {
+			continue
+		}
+		if rule.Match != nil && !rule.Match(req) {
+			continue
+		}
+
+		rule.matched++
+		if rule.matched <= rule.After {
+			continue
+		}
+		if rule.Times > 0 && rule.fired >= rule.Times {
+			continue
+		}
+		if rule.Probability > 0 && i.rng.Float64() >= rule.Probability {
+			continue
+		}
+
+		rule.fired++
+		i.fired = append(i.fired, fmt.Sprintf("%d %s %s", rule.index, req.Method, req.URL.RequestURI()))
+		return rule.Fault
+	}
+
+	return nil
+}
+
+// Transport wraps next (http.DefaultTransport if nil) so faults are injected into
+// real client traffic
+func (i *Injector) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &faultTransport{injector: i, next: next}
+}
+
+type faultTransport struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := t.injector.pick(req)
+	if fault == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	response, err := fault(req, func() (*Response, error) {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	header := response.Header.Clone()
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          &delayedBody{ctx: req.Context().Done(), delay: response.Delay, r: bytes.NewReader(response.Body)},
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// delayedBody waits before yielding its first byte, unless the request is canceled
+type delayedBody struct {
+	ctx     <-chan struct{}
+	delay   time.Duration
+	r       io.Reader
+	started bool
+}
+
+func (b *delayedBody) Read(p []byte) (int, error) {
+	if !b.started {
+		b.started = true
+		select {
+		case <-time.After(b.delay):
+		case <-b.ctx:
+			return 0, fmt.Errorf("body read canceled")
+		}
+	}
+	return b.r.Read(p)
+}
+
+func (b *delayedBody) Close() error {
+	return nil
+}
+
+// Middleware wraps a handler so faults are injected into what it serves
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fault := i.pick(r)
+		if fault == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		response, err := fault(r, func() (*Response, error) {
+			recorder := httptest.NewRecorder()
+			next.ServeHTTP(recorder, r)
+			return &Response{StatusCode: recorder.Code, Header: recorder.Header(), Body: recorder.Body.Bytes()}, nil
+		})
+		if err != nil {
+			// Simulate a dropped connection
+			panic(http.ErrAbortHandler)
+		}
+
+		for key, values := range response.Header {
+			w.Header()[key] = values
+		}
+		w.Header().Del("Content-Length")
+		w.WriteHeader(response.StatusCode)
+		if response.Delay > 0 {
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			select {
+			case <-time.After(response.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Write(response.Body)
+	})
+}
+
+// StatusFault answers with the given status without reaching the server. A non-zero
+// retryAfter is sent as a Retry-After header (in whole seconds), as Gamma does for 429s.
+func StatusFault(status int, retryAfter time.Duration) Fault {
+	return func(req *http.Request, next func() (*Response, error)) (*Response, error) {
+		header := http.Header{"Content-Type": []string{"application/json"}}
+		if retryAfter > 0 {
+			header.Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
+		}
+		body, _ := json.Marshal(map[string]string{
+			"type":  http.StatusText(status),
+			"error": "injected fault",
+		})
+		return &Response{StatusCode: status, Header: header, Body: body}, nil
+	}
+}
+
+// SlowBodyFault delivers the genuine response but holds its body back for delay
+func SlowBodyFault(delay time.Duration) Fault {
+	return func(req *http.Request, next func() (*Response, error)) (*Response, error) {
+		response, err := next()
+		if err != nil {
+			return nil, err
+		}
+		response.Delay = delay
+		return response, nil
+	}
+}
+
+// TruncatedBodyFault cuts the body on the wire in half. For gzip responses this
+// produces a truncated gzip stream.
+func TruncatedBodyFault() Fault {
+	return func(req *http.Request, next func() (*Response, error)) (*Response, error) {
+		response, err := next()
+		if err != nil {
+			return nil, err
+		}
+		response.Body = response.Body[:len(response.Body)/2]
+		return response, nil
+	}
+}
+
+// MalformedJSONFault replaces the body with JSON that does not parse, keeping the
+// response's encoding
+func MalformedJSONFault() Fault {
+	return func(req *http.Request, next func() (*Response, error)) (*Response, error) {
+		response, err := next()
+		if err != nil {
+			return nil, err
+		}
+		return response, response.rewrite(func(body []byte) ([]byte, error) {
+			return []byte(`{"events": [{"id": "1", "title": `), nil
+		})
+	}
+}
+
+// DuplicateRowsFault repeats the last n events of each keyset page at the start of
+// the following page, as if the cursor had slipped backwards
+func DuplicateRowsFault(n int) Fault {
+	var mu sync.Mutex
+	var previous []json.RawMessage
+
+	return keysetFault(func(page *keysetPage) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		carried := previous
+		previous = append([]json.RawMessage(nil), page.Events[max(0, len(page.Events)-n):]...)
+		page.Events = append(carried, page.Events...)
+	})
+}
+
+// SkipRowsFault drops the first n events of a keyset page while leaving its cursor
+// intact, so those rows are silently missing from a crawl
+func SkipRowsFault(n int) Fault {
+	return keysetFault(func(page *keysetPage) {
+		page.Events = page.Events[min(n, len(page.Events)):]
+	})
+}
+
+// ExpiredCursorFault rejects requests that carry an after_cursor as if the cursor
+// had expired
+func ExpiredCursorFault() Fault {
+	return func(req *http.Request, next func() (*Response, error)) (*Response, error) {
+		if req.URL.Query().Get("after_cursor") == "" {
+			return next()
+		}
+		body, _ := json.Marshal(map[string]string{
+			"type":  http.StatusText(http.StatusBadRequest),
+			"error": "cursor expired",
+		})
+		return &Response{
+			StatusCode: http.StatusBadRequest,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       body,
+		}, nil
+	}
+}
+
+// keysetPage is a keyset response with events left undecoded, so unknown fields
+// survive a rewrite
+type keysetPage struct {
+	Events     []json.RawMessage `json:"events"`
+	NextCursor string            `json:"next_cursor"`
+}
+
+func keysetFault(rewrite func(page *keysetPage)) Fault {
+	return func(req *http.Request, next func() (*Response, error)) (*Response, error) {
+		response, err := next()
+		if err != nil || response.StatusCode != http.StatusOK {
+			return response, err
+		}
+		return response, response.rewrite(func(body []byte) ([]byte, error) {
+			var page keysetPage
+			if err := json.Unmarshal(body, &page); err != nil {
+				return nil, fmt.Errorf("fault expected a keyset page: %w", err)
+			}
+			rewrite(&page)
+			return json.Marshal(page)
+		})
+	}
+}
+
+// rewrite replaces the decoded body, transparently handling gzip encoding
+func (r *Response) rewrite(fn func(body []byte) ([]byte, error)) error {
+	gzipped := strings.Contains(r.Header.Get("Content-Encoding"), "gzip")
+
+	body := r.Body
+	if gzipped {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		if body, err = io.ReadAll(gzipReader); err != nil {
+			return fmt.Errorf("failed to decompress body: %w", err)
+		}
+	}
+
+	body, err := fn(body)
+	if err != nil {
+		return err
+	}
+
+	if gzipped {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(body)
+		gz.Close()
+		body = buf.Bytes()
+	}
+
+	r.Body = body
+	return nil
+}
@@ -0,0 +1,187 @@
+package gammatest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crawl walks every keyset page, returning the IDs seen
+func crawl(t *testing.T, client *polymarket_gamma.Client) []string {
+	var seen []string
+	cursor := ""
+	for {
+		page, err := client.GetEventsByKeysetPage(cursor, 10)
+		require.NoError(t, err)
+		seen = append(seen, ids(page.Events)...)
+		if page.NextCursor == "" {
+			return seen
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func TestStatusFaultBurst(t *testing.T) {
+	injector := NewInjector(1, Rule{
+		Path:  "/events",
+		Times: 2,
+		Fault: StatusFault(http.StatusTooManyRequests, 3*time.Second),
+	})
+	server := NewServer(&Config{Events: seedEvents(3), Faults: injector})
+	defer server.Close()
+	client := server.Client()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetEventsByIDs([]int{1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "429")
+	}
+
+	response, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	assert.Len(t, response.Events, 1)
+	assert.Len(t, injector.Fired(), 2)
+}
+
+func TestStatusFaultRetryAfterViaTransport(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(3)})
+	defer server.Close()
+
+	injector := NewInjector(1, Rule{Fault: StatusFault(http.StatusTooManyRequests, 3*time.Second)})
+	var meta *polymarket_gamma.RequestInfo
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL:   server.URL,
+		Transport: injector.Transport(nil),
+		Observer: polymarket_gamma.ObserverFunc(func(ctx context.Context, info *polymarket_gamma.RequestInfo) {
+			meta = info
+		}),
+	})
+
+	_, err := client.GetEventsByIDs([]int{1})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, meta.StatusCode)
+	assert.Equal(t, "3", meta.Header.Get("Retry-After"))
+	assert.Empty(t, server.Requests(), "the fault answers without reaching the server")
+}
+
+func TestFlappingServerErrorsAreReproducible(t *testing.T) {
+	outcomes := func() []bool {
+		server := NewServer(&Config{
+			Events: seedEvents(3),
+			Faults: NewInjector(42, Rule{Probability: 0.5, Fault: StatusFault(http.StatusBadGateway, 0)}),
+		})
+		defer server.Close()
+		client := server.Client()
+
+		var result []bool
+		for i := 0; i < 20; i++ {
+			_, err := client.GetEventsByIDs([]int{1})
+			result = append(result, err == nil)
+		}
+		return result
+	}
+
+	first := outcomes()
+	assert.Equal(t, first, outcomes())
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
+
+func TestTruncatedGzip(t *testing.T) {
+	for name, wrap := range map[string]func(*Injector) (*Server, *polymarket_gamma.Client){
+		"server": func(injector *Injector) (*Server, *polymarket_gamma.Client) {
+			server := NewServer(&Config{Events: seedEvents(50), Faults: injector})
+			return server, server.Client()
+		},
+		"transport": func(injector *Injector) (*Server, *polymarket_gamma.Client) {
+			server := NewServer(&Config{Events: seedEvents(50)})
+			return server, polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+				BaseURL:   server.URL,
+				Transport: injector.Transport(nil),
+			})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server, client := wrap(NewInjector(1, Rule{Fault: TruncatedBodyFault()}))
+			defer server.Close()
+
+			_, err := client.GetEventsByPage(0, 50, true)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to read response body")
+		})
+	}
+}
+
+func TestMalformedJSON(t *testing.T) {
+	server := NewServer(&Config{
+		Events: seedEvents(3),
+		Faults: NewInjector(1, Rule{Path: "/events/keyset", Fault: MalformedJSONFault()}),
+	})
+	defer server.Close()
+
+	_, err := server.Client().GetEventsByKeysetPage("", 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse response")
+}
+
+func TestSlowBody(t *testing.T) {
+	server := NewServer(&Config{
+		Events: seedEvents(3),
+		Faults: NewInjector(1, Rule{Fault: SlowBodyFault(time.Second)}),
+	})
+	defer server.Close()
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL: server.URL,
+		Timeout: 100 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := client.GetEventsByIDs([]int{1})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestDuplicateAndSkippedRows(t *testing.T) {
+	duplicated := NewServer(&Config{
+		Events: seedEvents(25),
+		Faults: NewInjector(1, Rule{Path: "/events/keyset", Fault: DuplicateRowsFault(2)}),
+	})
+	defer duplicated.Close()
+
+	seen := crawl(t, duplicated.Client())
+	assert.Len(t, seen, 29)
+	assert.Equal(t, []string{"9", "10", "11"}, seen[10:13])
+
+	skipped := NewServer(&Config{
+		Events: seedEvents(25),
+		Faults: NewInjector(1, Rule{Path: "/events/keyset", After: 1, Times: 1, Fault: SkipRowsFault(3)}),
+	})
+	defer skipped.Close()
+
+	seen = crawl(t, skipped.Client())
+	assert.Len(t, seen, 22)
+	assert.NotContains(t, seen, "11")
+	assert.Contains(t, seen, "14")
+}
+
+func TestExpiredCursor(t *testing.T) {
+	server := NewServer(&Config{
+		Events: seedEvents(25),
+		Faults: NewInjector(1, Rule{Path: "/events/keyset", Fault: ExpiredCursorFault()}),
+	})
+	defer server.Close()
+	client := server.Client()
+
+	page, err := client.GetEventsByKeysetPage("", 10)
+	require.NoError(t, err)
+
+	_, err = client.GetEventsByKeysetPage(page.NextCursor, 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cursor expired")
+}
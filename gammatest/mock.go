@@ -0,0 +1,236 @@
+package gammatest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+// MockCall records one call made to a mock
+type MockCall struct {
+	// Method is the name of the ...Context method that handled the call
+	Method string
+	Args   []any
+}
+
+// MockEventsAPI is a configurable polymarket_gamma.EventsAPI for unit tests that
+// should not touch HTTP. Set the ...Func fields for the calls a test expects; calling
+// a method whose Func is nil returns an error. The methods without a context
+// delegate to their ...Context counterparts, so one Func covers both.
+type MockEventsAPI struct {
+	GetEventsByIDsFunc              func(ctx context.Context, ids []int) (*polymarket_gamma.GetEventsResponse, error)
+	GetEventsByPageFunc             func(ctx context.Context, offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error)
+	GetActiveEventsByPageFunc       func(ctx context.Context, offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error)
+	GetEventsByUpdatedAtPageFunc    func(ctx context.Context, offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error)
+	GetEventsByKeysetPageFunc       func(ctx context.Context, afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error)
+	GetActiveEventsByKeysetPageFunc func(ctx context.Context, afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error)
+	QueryEventsFunc                 func(ctx context.Context, query *polymarket_gamma.EventQuery) (*polymarket_gamma.GetEventsResponse, error)
+
+	mockCalls
+}
+
+// MockMarketsAPI is a configurable polymarket_gamma.MarketsAPI, used like MockEventsAPI
+type MockMarketsAPI struct {
+	GetMarketsByIDsFunc func(ctx context.Context, ids []int) (*polymarket_gamma.GetMarketsResponse, error)
+	QueryMarketsFunc    func(ctx context.Context, query *polymarket_gamma.MarketQuery) (*polymarket_gamma.GetMarketsResponse, error)
+
+	mockCalls
+}
+
+// MockTagsAPI is a configurable polymarket_gamma.TagsAPI, used like MockEventsAPI
+type MockTagsAPI struct {
+	GetTagsByPageFunc func(ctx context.Context, offset, limit int) (*polymarket_gamma.GetTagsResponse, error)
+
+	mockCalls
+}
+
+// MockSeriesAPI is a configurable polymarket_gamma.SeriesAPI, used like MockEventsAPI
+type MockSeriesAPI struct {
+	GetSeriesByPageFunc func(ctx context.Context, offset, limit int) (*polymarket_gamma.GetSeriesResponse, error)
+
+	mockCalls
+}
+
+// MockSearchAPI is a configurable polymarket_gamma.SearchAPI, used like MockEventsAPI
+type MockSearchAPI struct {
+	SearchFunc func(ctx context.Context, query string, limitPerType int) (*polymarket_gamma.SearchResponse, error)
+
+	mockCalls
+}
+
+var (
+	_ polymarket_gamma.EventsAPI  = (*MockEventsAPI)(nil)
+	_ polymarket_gamma.MarketsAPI = (*MockMarketsAPI)(nil)
+	_ polymarket_gamma.TagsAPI    = (*MockTagsAPI)(nil)
+	_ polymarket_gamma.SeriesAPI  = (*MockSeriesAPI)(nil)
+	_ polymarket_gamma.SearchAPI  = (*MockSearchAPI)(nil)
+)
+
+// mockCalls records the calls made to a mock
+type mockCalls struct {
+	mu    sync.Mutex
+	calls []MockCall
+}
+
+// Calls returns every call made so far, in order
+func (m *mockCalls) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+func (m *mockCalls) record(method string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, MockCall{Method: method, Args: args})
+}
+
+func notConfigured(mock, method string) error {
+	return fmt.Errorf("gammatest: %s.%sFunc is not set", mock, method)
+}
+
+func (m *MockEventsAPI) GetEventsByIDs(ids []int) (*polymarket_gamma.GetEventsResponse, error) {
+	return m.GetEventsByIDsContext(context.Background(), ids)
+}
+
+func (m *MockEventsAPI) GetEventsByIDsContext(ctx context.Context, ids []int) (*polymarket_gamma.GetEventsResponse, error) {
+	m.record("GetEventsByIDs", ids)
+	if m.GetEventsByIDsFunc == nil {
+		return nil, notConfigured("MockEventsAPI", "GetEventsByIDs")
+	}
+	return m.GetEventsByIDsFunc(ctx, ids)
+}
+
+func (m *MockEventsAPI) GetEventsByPage(offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error) {
+	return m.GetEventsByPageContext(context.Background(), offset, limit, ascending)
+}
+
+func (m *MockEventsAPI) GetEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error) {
+	m.record("GetEventsByPage", offset, limit, ascending)
+	if m.GetEventsByPageFunc == nil {
+		return nil, notConfigured("MockEventsAPI", "GetEventsByPage")
+	}
+	return m.GetEventsByPageFunc(ctx, offset, limit, ascending)
+}
+
+func (m *MockEventsAPI) GetActiveEventsByPage(offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error) {
+	return m.GetActiveEventsByPageContext(context.Background(), offset, limit, ascending)
+}
+
+func (m *MockEventsAPI) GetActiveEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error) {
+	m.record("GetActiveEventsByPage", offset, limit, ascending)
+	if m.GetActiveEventsByPageFunc == nil {
+		return nil, notConfigured("MockEventsAPI", "GetActiveEventsByPage")
+	}
+	return m.GetActiveEventsByPageFunc(ctx, offset, limit, ascending)
+}
+
+func (m *MockEventsAPI) GetEventsByUpdatedAtPage(offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error) {
+	return m.GetEventsByUpdatedAtPageContext(context.Background(), offset, limit, ascending)
+}
+
+func (m *MockEventsAPI) GetEventsByUpdatedAtPageContext(ctx context.Context, offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error) {
+	m.record("GetEventsByUpdatedAtPage", offset, limit, ascending)
+	if m.GetEventsByUpdatedAtPageFunc == nil {
+		return nil, notConfigured("MockEventsAPI", "GetEventsByUpdatedAtPage")
+	}
+	return m.GetEventsByUpdatedAtPageFunc(ctx, offset, limit, ascending)
+}
+
+func (m *MockEventsAPI) GetEventsByKeysetPage(afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error) {
+	return m.GetEventsByKeysetPageContext(context.Background(), afterCursor, limit)
+}
+
+func (m *MockEventsAPI) GetEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error) {
+	m.record("GetEventsByKeysetPage", afterCursor, limit)
+	if m.GetEventsByKeysetPageFunc == nil {
+		return nil, notConfigured("MockEventsAPI", "GetEventsByKeysetPage")
+	}
+	return m.GetEventsByKeysetPageFunc(ctx, afterCursor, limit)
+}
+
+func (m *MockEventsAPI) GetActiveEventsByKeysetPage(afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error) {
+	return m.GetActiveEventsByKeysetPageContext(context.Background(), afterCursor, limit)
+}
+
+func (m *MockEventsAPI) GetActiveEventsByKeysetPageContext(ctx context.Context, afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error) {
+	m.record("GetActiveEventsByKeysetPage", afterCursor, limit)
+	if m.GetActiveEventsByKeysetPageFunc == nil {
+		return nil, notConfigured("MockEventsAPI", "GetActiveEventsByKeysetPage")
+	}
+	return m.GetActiveEventsByKeysetPageFunc(ctx, afterCursor, limit)
+}
+
+func (m *MockEventsAPI) QueryEvents(query *polymarket_gamma.EventQuery) (*polymarket_gamma.GetEventsResponse, error) {
+	return m.QueryEventsContext(context.Background(), query)
+}
+
+func (m *MockEventsAPI) QueryEventsContext(ctx context.Context, query *polymarket_gamma.EventQuery) (*polymarket_gamma.GetEventsResponse, error) {
+	m.record("QueryEvents", query)
+	if m.QueryEventsFunc == nil {
+		return nil, notConfigured("MockEventsAPI", "QueryEvents")
+	}
+	return m.QueryEventsFunc(ctx, query)
+}
+
+func (m *MockMarketsAPI) GetMarketsByIDs(ids []int) (*polymarket_gamma.GetMarketsResponse, error) {
+	return m.GetMarketsByIDsContext(context.Background(), ids)
+}
+
+func (m *MockMarketsAPI) GetMarketsByIDsContext(ctx context.Context, ids []int) (*polymarket_gamma.GetMarketsResponse, error) {
+	m.record("GetMarketsByIDs", ids)
+	if m.GetMarketsByIDsFunc == nil {
+		return nil, notConfigured("MockMarketsAPI", "GetMarketsByIDs")
+	}
+	return m.GetMarketsByIDsFunc(ctx, ids)
+}
+
+func (m *MockMarketsAPI) QueryMarkets(query *polymarket_gamma.MarketQuery) (*polymarket_gamma.GetMarketsResponse, error) {
+	return m.QueryMarketsContext(context.Background(), query)
+}
+
+func (m *MockMarketsAPI) QueryMarketsContext(ctx context.Context, query *polymarket_gamma.MarketQuery) (*polymarket_gamma.GetMarketsResponse, error) {
+	m.record("QueryMarkets", query)
+	if m.QueryMarketsFunc == nil {
+		return nil, notConfigured("MockMarketsAPI", "QueryMarkets")
+	}
+	return m.QueryMarketsFunc(ctx, query)
+}
+
+func (m *MockTagsAPI) GetTagsByPage(offset, limit int) (*polymarket_gamma.GetTagsResponse, error) {
+	return m.GetTagsByPageContext(context.Background(), offset, limit)
+}
+
+func (m *MockTagsAPI) GetTagsByPageContext(ctx context.Context, offset, limit int) (*polymarket_gamma.GetTagsResponse, error) {
+	m.record("GetTagsByPage", offset, limit)
+	if m.GetTagsByPageFunc == nil {
+		return nil, notConfigured("MockTagsAPI", "GetTagsByPage")
+	}
+	return m.GetTagsByPageFunc(ctx, offset, limit)
+}
+
+func (m *MockSeriesAPI) GetSeriesByPage(offset, limit int) (*polymarket_gamma.GetSeriesResponse, error) {
+	return m.GetSeriesByPageContext(context.Background(), offset, limit)
+}
+
+func (m *MockSeriesAPI) GetSeriesByPageContext(ctx context.Context, offset, limit int) (*polymarket_gamma.GetSeriesResponse, error) {
+	m.record("GetSeriesByPage", offset, limit)
+	if m.GetSeriesByPageFunc == nil {
+		return nil, notConfigured("MockSeriesAPI", "GetSeriesByPage")
+	}
+	return m.GetSeriesByPageFunc(ctx, offset, limit)
+}
+
+func (m *MockSearchAPI) Search(query string, limitPerType int) (*polymarket_gamma.SearchResponse, error) {
+	return m.SearchContext(context.Background(), query, limitPerType)
+}
+
+func (m *MockSearchAPI) SearchContext(ctx context.Context, query string, limitPerType int) (*polymarket_gamma.SearchResponse, error) {
+	m.record("Search", query, limitPerType)
+	if m.SearchFunc == nil {
+		return nil, notConfigured("MockSearchAPI", "Search")
+	}
+	return m.SearchFunc(ctx, query, limitPerType)
+}
@@ -0,0 +1,85 @@
+package gammatest
+
+import (
+	"context"
+	"testing"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countEvents stands in for consumer code that only depends on the interface
+func countEvents(api polymarket_gamma.EventsAPI) (int, error) {
+	total := 0
+	cursor := ""
+	for {
+		page, err := api.GetEventsByKeysetPage(cursor, 100)
+		if err != nil {
+			return 0, err
+		}
+		total += len(page.Events)
+		if page.NextCursor == "" {
+			return total, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func TestMockEventsAPI(t *testing.T) {
+	mock := &MockEventsAPI{
+		GetEventsByKeysetPageFunc: func(ctx context.Context, afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error) {
+			if afterCursor == "" {
+				return &polymarket_gamma.GetEventsKeysetResponse{
+					Events:     []polymarket_gamma.Event{{ID: "1"}, {ID: "2"}},
+					NextCursor: "next",
+				}, nil
+			}
+			return &polymarket_gamma.GetEventsKeysetResponse{
+				Events: []polymarket_gamma.Event{{ID: "3"}},
+			}, nil
+		},
+	}
+
+	total, err := countEvents(mock)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	assert.Equal(t, []MockCall{
+		{Method: "GetEventsByKeysetPage", Args: []any{"", 100}},
+		{Method: "GetEventsByKeysetPage", Args: []any{"next", 100}},
+	}, mock.Calls())
+}
+
+func TestMockEventsAPINotConfigured(t *testing.T) {
+	mock := &MockEventsAPI{}
+
+	_, err := mock.GetEventsByIDsContext(context.Background(), []int{1})
+	assert.EqualError(t, err, "gammatest: MockEventsAPI.GetEventsByIDsFunc is not set")
+	assert.Len(t, mock.Calls(), 1)
+}
+
+func TestResourceMocks(t *testing.T) {
+	markets := &MockMarketsAPI{
+		GetMarketsByIDsFunc: func(ctx context.Context, ids []int) (*polymarket_gamma.GetMarketsResponse, error) {
+			return &polymarket_gamma.GetMarketsResponse{Markets: []polymarket_gamma.Market{{ID: "7"}}}, nil
+		},
+	}
+	response, err := markets.GetMarketsByIDs([]int{7})
+	require.NoError(t, err)
+	assert.Equal(t, "7", response.Markets[0].ID)
+	assert.Equal(t, []MockCall{{Method: "GetMarketsByIDs", Args: []any{[]int{7}}}}, markets.Calls())
+
+	_, err = markets.QueryMarkets(nil)
+	assert.EqualError(t, err, "gammatest: MockMarketsAPI.QueryMarketsFunc is not set")
+
+	search := &MockSearchAPI{}
+	_, err = search.Search("nba", 5)
+	assert.EqualError(t, err, "gammatest: MockSearchAPI.SearchFunc is not set")
+	assert.Equal(t, []MockCall{{Method: "Search", Args: []any{"nba", 5}}}, search.Calls())
+
+	_, err = (&MockTagsAPI{}).GetTagsByPage(0, 10)
+	assert.EqualError(t, err, "gammatest: MockTagsAPI.GetTagsByPageFunc is not set")
+	_, err = (&MockSeriesAPI{}).GetSeriesByPage(0, 10)
+	assert.EqualError(t, err, "gammatest: MockSeriesAPI.GetSeriesByPageFunc is not set")
+}
@@ -0,0 +1,695 @@
+// Package gammatest provides an in-memory fake of the Polymarket Gamma API for tests.
+//
+// The fake server is seeded with events, markets, tags and series and serves
+// /events, /events/keyset, /events/{id}, /markets, /markets/{id}, /tags, /series and
+// /public-search with the same filtering, ordering, offset cap (422), page size cap and keyset cursor
+// behaviour as the real API, gzip-compressing responses when asked to.
+//
+//	server := gammatest.NewServer(&gammatest.Config{
+//	    Events: []polymarket_gamma.Event{{ID: "1", Title: "Test"}},
+//	})
+//	defer server.Close()
+//
+//	client := server.Client()
+//	response, err := client.GetEventsByIDs([]int{1})
+package gammatest
+
+import (
+	"cmp"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+const (
+	// DefaultMaxOffset mirrors the offset above which /events returns 422
+	DefaultMaxOffset = 2500
+	// DefaultMaxLimit mirrors the row cap applied to every page
+	DefaultMaxLimit = 100
+	// defaultLimit is used when a request has no limit parameter
+	defaultLimit = 20
+)
+
+type Config struct {
+	Events []polymarket_gamma.Event
+	// Markets are served from /markets in addition to the markets nested in Events
+	Markets []polymarket_gamma.Market
+	Tags    []polymarket_gamma.Tag
+	Series  []polymarket_gamma.Series
+	// RelatedTags maps a tag ID to the IDs of its related tags, which tag_id also
+	// matches when a request sets related_tags=true
+	RelatedTags map[string][]string
+	// MaxOffset is the largest accepted offset (default DefaultMaxOffset)
+	MaxOffset int
+	// MaxLimit silently caps the rows returned per page (default DefaultMaxLimit)
+	MaxLimit int
+	// Faults injects misbehaviour into what the server returns (optional)
+	Faults *Injector
+}
+
+// Server is a running fake Gamma API. Its data can be changed while it is serving,
+// which is useful for testing pollers and incremental sync.
+type Server struct {
+	*httptest.Server
+
+	maxOffset int
+	maxLimit  int
+
+	mu       sync.RWMutex
+	events   []polymarket_gamma.Event
+	markets  []polymarket_gamma.Market
+	tags     []polymarket_gamma.Tag
+	series   []polymarket_gamma.Series
+	related  map[string][]string
+	requests []string
+}
+
+// NewServer starts a fake server seeded from config
+func NewServer(config *Config) *Server {
+	s := newServer(config)
+
+	var handler http.Handler = s
+	if config != nil && config.Faults != nil {
+		handler = config.Faults.Middleware(handler)
+	}
+	s.Server = httptest.NewServer(handler)
+
+	return s
+}
+
+func newServer(config *Config) *Server {
+	if config == nil {
+		config = &Config{}
+	}
+
+	s := &Server{
+		maxOffset: config.MaxOffset,
+		maxLimit:  config.MaxLimit,
+		events:    slices.Clone(config.Events),
+		markets:   slices.Clone(config.Markets),
+		tags:      slices.Clone(config.Tags),
+		series:    slices.Clone(config.Series),
+		related:   maps.Clone(config.RelatedTags),
+	}
+	if s.maxOffset == 0 {
+		s.maxOffset = DefaultMaxOffset
+	}
+	if s.maxLimit == 0 {
+		s.maxLimit = DefaultMaxLimit
+	}
+
+	return s
+}
+
+// Client returns a client pointed at the fake server
+func (s *Server) Client() *polymarket_gamma.Client {
+	return polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL: s.URL,
+	})
+}
+
+// SetEvents replaces every event served
+func (s *Server) SetEvents(events []polymarket_gamma.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = slices.Clone(events)
+}
+
+// UpsertEvent adds event, or replaces the event with the same ID
+func (s *Server) UpsertEvent(event polymarket_gamma.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.events {
+		if s.events[i].ID == event.ID {
+			s.events[i] = event
+			return
+		}
+	}
+	s.events = append(s.events, event)
+}
+
+// RemoveEvent stops serving the event with the given ID
+func (s *Server) RemoveEvent(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = slices.DeleteFunc(s.events, func(e polymarket_gamma.Event) bool { return e.ID == id })
+}
+
+// Requests returns the path and query of every request served so far
+func (s *Server) Requests() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	s.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/events":
+		s.serveEvents(w, r)
+	case path == "/events/keyset":
+		s.serveEventsKeyset(w, r)
+	case strings.HasPrefix(path, "/events/"):
+		s.serveEvent(w, r, strings.TrimPrefix(path, "/events/"))
+	case path == "/markets":
+		s.serveMarkets(w, r)
+	case strings.HasPrefix(path, "/markets/"):
+		s.serveMarket(w, r, strings.TrimPrefix(path, "/markets/"))
+	case path == "/tags":
+		serveList(w, r, s, s.tags)
+	case path == "/series":
+		serveList(w, r, s, filterSeries(s.series, r.URL.Query()))
+	case path == "/public-search":
+		s.serveSearch(w, r)
+	default:
+		writeError(w, r, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	offset, limit, ok := s.page(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := filterEvents(s.events, query, s.related, offsetParams)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	compare, ok := eventOrders[cmp.Or(query.Get("order"), "id")]
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("unsupported order %q", query.Get("order")))
+		return
+	}
+	ascending := query.Get("ascending") == "true"
+	slices.SortStableFunc(events, func(a, b polymarket_gamma.Event) int {
+		if ascending {
+			return compare(a, b)
+		}
+		return compare(b, a)
+	})
+
+	writeJSON(w, r, paginate(events, offset, limit))
+}
+
+func (s *Server) serveEventsKeyset(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	_, limit, ok := s.page(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := filterEvents(s.events, query, s.related, keysetParams)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	slices.SortStableFunc(events, eventOrders["id"])
+
+	if cursor := query.Get("after_cursor"); cursor != "" {
+		afterID, err := DecodeCursor(cursor)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid after_cursor")
+			return
+		}
+		events = slices.DeleteFunc(events, func(e polymarket_gamma.Event) bool {
+			return compareIDs(e.ID, afterID) <= 0
+		})
+	}
+
+	response := polymarket_gamma.GetEventsKeysetResponse{
+		Events: paginate(events, 0, limit),
+	}
+	if limit > 0 && len(events) > limit {
+		response.NextCursor = EncodeCursor(response.Events[len(response.Events)-1].ID)
+	}
+
+	writeJSON(w, r, response)
+}
+
+func (s *Server) serveEvent(w http.ResponseWriter, r *http.Request, id string) {
+	for _, event := range s.events {
+		if event.ID == id {
+			writeJSON(w, r, event)
+			return
+		}
+	}
+	writeError(w, r, http.StatusNotFound, "event not found")
+}
+
+func (s *Server) serveMarkets(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	offset, limit, ok := s.page(w, r)
+	if !ok {
+		return
+	}
+
+	markets, err := filterMarkets(s.allMarkets(), query, s.related)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	compare, ok := marketOrders[cmp.Or(query.Get("order"), "id")]
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("unsupported order %q", query.Get("order")))
+		return
+	}
+	ascending := query.Get("ascending") == "true"
+	slices.SortStableFunc(markets, func(a, b polymarket_gamma.Market) int {
+		if ascending {
+			return compare(a, b)
+		}
+		return compare(b, a)
+	})
+
+	writeJSON(w, r, paginate(markets, offset, limit))
+}
+
+func (s *Server) serveMarket(w http.ResponseWriter, r *http.Request, id string) {
+	for _, market := range s.allMarkets() {
+		if market.ID == id {
+			writeJSON(w, r, market)
+			return
+		}
+	}
+	writeError(w, r, http.StatusNotFound, "market not found")
+}
+
+// serveSearch matches q case-insensitively against event titles and slugs and tag
+// labels and slugs, returning at most limit_per_type of each
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := strings.ToLower(query.Get("q"))
+	if q == "" {
+		writeError(w, r, http.StatusBadRequest, "q is required")
+		return
+	}
+	limit, err := intParam(query.Get("limit_per_type"), defaultLimit)
+	if err != nil || limit < 0 {
+		writeError(w, r, http.StatusBadRequest, "invalid limit_per_type")
+		return
+	}
+
+	matches := func(fields ...string) bool {
+		return slices.ContainsFunc(fields, func(field string) bool {
+			return strings.Contains(strings.ToLower(field), q)
+		})
+	}
+	var response polymarket_gamma.SearchResponse
+	for _, event := range s.events {
+		if matches(event.Title, event.Slug) {
+			response.Events = append(response.Events, event)
+		}
+	}
+	for _, tag := range s.tags {
+		if matches(tag.Label, tag.Slug) {
+			response.Tags = append(response.Tags, tag)
+		}
+	}
+
+	response.Pagination.TotalResults = len(response.Events)
+	response.Pagination.HasMore = len(response.Events) > limit
+	response.Events = paginate(response.Events, 0, limit)
+	response.Tags = paginate(response.Tags, 0, limit)
+	writeJSON(w, r, response)
+}
+
+// allMarkets returns the standalone markets followed by those nested in events
+func (s *Server) allMarkets() []polymarket_gamma.Market {
+	markets := slices.Clone(s.markets)
+	for _, event := range s.events {
+		markets = append(markets, event.Markets...)
+	}
+	return markets
+}
+
+// page parses offset and limit, enforcing the offset cap and silently capping limit
+func (s *Server) page(w http.ResponseWriter, r *http.Request) (offset, limit int, ok bool) {
+	query := r.URL.Query()
+
+	offset, err := intParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, r, http.StatusBadRequest, "invalid offset")
+		return 0, 0, false
+	}
+	if offset > s.maxOffset {
+		writeError(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("offset must be at most %d", s.maxOffset))
+		return 0, 0, false
+	}
+
+	limit, err = intParam(query.Get("limit"), defaultLimit)
+	if err != nil || limit < 0 {
+		writeError(w, r, http.StatusBadRequest, "invalid limit")
+		return 0, 0, false
+	}
+
+	return offset, min(limit, s.maxLimit), true
+}
+
+func serveList[T any](w http.ResponseWriter, r *http.Request, s *Server, items []T) {
+	offset, limit, ok := s.page(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, r, paginate(items, offset, limit))
+}
+
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	return slices.Clone(items[offset:min(offset+limit, len(items))])
+}
+
+// offsetParams and keysetParams are the paging parameters of the offset and keyset
+// endpoints, accepted alongside the filters
+var (
+	offsetParams = []string{"offset", "limit", "order", "ascending"}
+	keysetParams = []string{"limit", "after_cursor"}
+)
+
+func eventFilters(event polymarket_gamma.Event) map[string]func(string) (bool, error) {
+	return map[string]func(string) (bool, error){
+		"id":             equals(event.ID),
+		"slug":           equals(event.Slug),
+		"closed":         boolEquals(event.Closed),
+		"active":         boolEquals(event.Active),
+		"archived":       boolEquals(event.Archived),
+		"featured":       boolEquals(event.Featured),
+		"tag_slug":       hasTag(event.Tags, func(t polymarket_gamma.Tag) string { return t.Slug }),
+		"tag_id":         hasTag(event.Tags, func(t polymarket_gamma.Tag) string { return t.ID }),
+		"series_id":      hasSeries(event.Series),
+		"liquidity_min":  atLeast(event.Liquidity),
+		"liquidity_max":  atMost(event.Liquidity),
+		"volume_min":     atLeast(event.Volume),
+		"volume_max":     atMost(event.Volume),
+		"start_date_min": notBefore(event.StartDate),
+		"start_date_max": notAfter(event.StartDate),
+		"end_date_min":   notBefore(event.EndDate),
+		"end_date_max":   notAfter(event.EndDate),
+	}
+}
+
+func marketFilters(market polymarket_gamma.Market) map[string]func(string) (bool, error) {
+	return map[string]func(string) (bool, error){
+		"id":                equals(market.ID),
+		"slug":              equals(market.Slug),
+		"condition_ids":     equals(market.ConditionID),
+		"clob_token_ids":    containsToken(market.ClobTokenIds),
+		"closed":            boolEquals(market.Closed),
+		"active":            boolEquals(market.Active),
+		"archived":          boolEquals(market.Archived),
+		"tag_slug":          hasTag(market.Tags, func(t polymarket_gamma.Tag) string { return t.Slug }),
+		"tag_id":            hasTag(market.Tags, func(t polymarket_gamma.Tag) string { return t.ID }),
+		"liquidity_num_min": atLeast(market.LiquidityNum),
+		"liquidity_num_max": atMost(market.LiquidityNum),
+		"volume_num_min":    atLeast(market.VolumeNum),
+		"volume_num_max":    atMost(market.VolumeNum),
+		"start_date_min":    notBefore(market.StartDate),
+		"start_date_max":    notAfter(market.StartDate),
+		"end_date_min":      notBefore(market.EndDate),
+		"end_date_max":      notAfter(market.EndDate),
+	}
+}
+
+func filterEvents(events []polymarket_gamma.Event, query url.Values, related map[string][]string, paging []string) ([]polymarket_gamma.Event, error) {
+	query, err := prepareQuery(query, related, eventFilters(polymarket_gamma.Event{}), paging)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []polymarket_gamma.Event
+	for _, event := range events {
+		match, err := matchAll(query, eventFilters(event))
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered, nil
+}
+
+func filterMarkets(markets []polymarket_gamma.Market, query url.Values, related map[string][]string) ([]polymarket_gamma.Market, error) {
+	query, err := prepareQuery(query, related, marketFilters(polymarket_gamma.Market{}), offsetParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []polymarket_gamma.Market
+	for _, market := range markets {
+		match, err := matchAll(query, marketFilters(market))
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			filtered = append(filtered, market)
+		}
+	}
+	return filtered, nil
+}
+
+// prepareQuery rejects parameters that are neither filters nor paging, so a test
+// relying on a filter the fake lacks fails loudly, and widens tag_id to the related
+// tags when related_tags=true
+func prepareQuery(query url.Values, related map[string][]string, filters map[string]func(string) (bool, error), paging []string) (url.Values, error) {
+	for name := range query {
+		if _, ok := filters[name]; !ok && name != "related_tags" && !slices.Contains(paging, name) {
+			return nil, fmt.Errorf("unsupported parameter %q", name)
+		}
+	}
+
+	value := query.Get("related_tags")
+	if value == "" {
+		return query, nil
+	}
+	expand, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid related_tags: %w", err)
+	}
+	if !expand || len(query["tag_id"]) == 0 {
+		return query, nil
+	}
+
+	widened := maps.Clone(query)
+	widened["tag_id"] = slices.Clone(query["tag_id"])
+	for _, id := range query["tag_id"] {
+		widened["tag_id"] = append(widened["tag_id"], related[id]...)
+	}
+	return widened, nil
+}
+
+func filterSeries(series []polymarket_gamma.Series, query map[string][]string) []polymarket_gamma.Series {
+	slugs := query["slug"]
+	if len(slugs) == 0 {
+		return series
+	}
+	var filtered []polymarket_gamma.Series
+	for _, s := range series {
+		if slices.Contains(slugs, s.Slug) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// matchAll reports whether a record satisfies every filter present in query. A
+// parameter repeated several times matches if any of its values match.
+func matchAll(query map[string][]string, filters map[string]func(string) (bool, error)) (bool, error) {
+	for name, filter := range filters {
+		values, ok := query[name]
+		if !ok {
+			continue
+		}
+
+		matched := false
+		for _, value := range values {
+			match, err := filter(value)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			matched = matched || match
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func equals(field string) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		return field == value, nil
+	}
+}
+
+func boolEquals(field bool) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		want, err := strconv.ParseBool(value)
+		return field == want, err
+	}
+}
+
+func hasTag(tags []polymarket_gamma.Tag, key func(polymarket_gamma.Tag) string) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		return slices.ContainsFunc(tags, func(t polymarket_gamma.Tag) bool { return key(t) == value }), nil
+	}
+}
+
+func hasSeries(series []polymarket_gamma.Series) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		return slices.ContainsFunc(series, func(s polymarket_gamma.Series) bool { return s.ID == value }), nil
+	}
+}
+
+func atLeast(field float64) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		bound, err := strconv.ParseFloat(value, 64)
+		return field >= bound, err
+	}
+}
+
+func atMost(field float64) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		bound, err := strconv.ParseFloat(value, 64)
+		return field <= bound, err
+	}
+}
+
+func notBefore(field time.Time) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		bound, err := time.Parse(time.RFC3339, value)
+		return !field.Before(bound), err
+	}
+}
+
+func notAfter(field time.Time) func(string) (bool, error) {
+	return func(value string) (bool, error) {
+		bound, err := time.Parse(time.RFC3339, value)
+		return !field.After(bound), err
+	}
+}
+
+// containsToken matches against the JSON-encoded token ID list Gamma stores on markets
+func containsToken(encoded string) func(string) (bool, error) {
+	var tokens []string
+	json.Unmarshal([]byte(encoded), &tokens)
+	return func(value string) (bool, error) {
+		return slices.Contains(tokens, value), nil
+	}
+}
+
+var eventOrders = map[string]func(a, b polymarket_gamma.Event) int{
+	"id":         func(a, b polymarket_gamma.Event) int { return compareIDs(a.ID, b.ID) },
+	"volume":     func(a, b polymarket_gamma.Event) int { return cmp.Compare(a.Volume, b.Volume) },
+	"volume24hr": func(a, b polymarket_gamma.Event) int { return cmp.Compare(a.Volume24hr, b.Volume24hr) },
+	"liquidity":  func(a, b polymarket_gamma.Event) int { return cmp.Compare(a.Liquidity, b.Liquidity) },
+	"startDate":  func(a, b polymarket_gamma.Event) int { return a.StartDate.Compare(b.StartDate) },
+	"endDate":    func(a, b polymarket_gamma.Event) int { return a.EndDate.Compare(b.EndDate) },
+	"createdAt":  func(a, b polymarket_gamma.Event) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updatedAt":  func(a, b polymarket_gamma.Event) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+}
+
+var marketOrders = map[string]func(a, b polymarket_gamma.Market) int{
+	"id":         func(a, b polymarket_gamma.Market) int { return compareIDs(a.ID, b.ID) },
+	"volume":     func(a, b polymarket_gamma.Market) int { return cmp.Compare(a.VolumeNum, b.VolumeNum) },
+	"volume24hr": func(a, b polymarket_gamma.Market) int { return cmp.Compare(a.Volume24hr, b.Volume24hr) },
+	"liquidity":  func(a, b polymarket_gamma.Market) int { return cmp.Compare(a.LiquidityNum, b.LiquidityNum) },
+	"endDate":    func(a, b polymarket_gamma.Market) int { return a.EndDate.Compare(b.EndDate) },
+	"updatedAt":  func(a, b polymarket_gamma.Market) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+}
+
+// compareIDs orders numeric IDs numerically, falling back to string order
+func compareIDs(a, b string) int {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return cmp.Compare(x, y)
+	}
+	return cmp.Compare(a, b)
+}
+
+// EncodeCursor returns the opaque keyset cursor pointing just after the event ID
+func EncodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("id:" + id))
+}
+
+// DecodeCursor returns the event ID a keyset cursor points after
+func DecodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode cursor: %w", err)
+	}
+	id, ok := strings.CutPrefix(string(decoded), "id:")
+	if !ok {
+		return "", fmt.Errorf("malformed cursor %q", cursor)
+	}
+	return id, nil
+}
+
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	writeResponse(w, r, http.StatusOK, v)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	writeResponse(w, r, status, map[string]string{
+		"type":  http.StatusText(status),
+		"error": message,
+	})
+}
+
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(status)
+	gz := gzip.NewWriter(w)
+	defer gz.Close()
+	json.NewEncoder(gz).Encode(v)
+}
@@ -0,0 +1,431 @@
+package gammatest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedEvents(n int) []polymarket_gamma.Event {
+	events := make([]polymarket_gamma.Event, 0, n)
+	for i := n; i >= 1; i-- {
+		id := strconv.Itoa(i)
+		events = append(events, polymarket_gamma.Event{
+			ID:        id,
+			Slug:      "event-" + id,
+			Title:     "Event " + id,
+			Closed:    i%2 == 0,
+			Volume:    float64(i * 10),
+			UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(n-i) * time.Hour),
+			Markets: []polymarket_gamma.Market{
+				{ID: "m" + id, ConditionID: "0xc" + id, ClobTokenIds: `["t` + id + `a", "t` + id + `b"]`},
+			},
+			Tags: []polymarket_gamma.Tag{{ID: "1", Slug: "all"}},
+		})
+	}
+	return events
+}
+
+func ids(events []polymarket_gamma.Event) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, event.ID)
+	}
+	return result
+}
+
+func getJSON(t *testing.T, server *Server, path string, v any) int {
+	resp, err := http.Get(server.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp.StatusCode
+}
+
+func TestEventsByID(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(5)})
+	defer server.Close()
+
+	response, err := server.Client().GetEventsByIDs([]int{4, 2, 9})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "2"}, ids(response.Events))
+	assert.Equal(t, []string{"/events?id=4&id=2&id=9&limit=3"}, server.Requests())
+}
+
+func TestEventsByIDPastDefaultLimit(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(150)})
+	defer server.Close()
+
+	ids := make([]int, 120)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	// Chunks of 100 and 20 IDs, each well past the API's default of 20 rows
+	response, err := server.Client().GetEventsByIDs(ids)
+	require.NoError(t, err)
+	assert.Len(t, response.Events, 120)
+	assert.Empty(t, response.MissingIDs)
+}
+
+func TestShardedCrawlPastDefaultLimit(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(250)})
+	defer server.Close()
+
+	var crawled []string
+	crawler := polymarket_gamma.NewShardedCrawler(server.Client(), &polymarket_gamma.ShardedCrawlerConfig{
+		BlockSize: 100,
+		Sink: func(ctx context.Context, events []polymarket_gamma.Event) error {
+			crawled = append(crawled, ids(events)...)
+			return nil
+		},
+	})
+	require.NoError(t, crawler.Run(context.Background()))
+	assert.Len(t, crawled, 250, "every block of 100 IDs is returned in full")
+}
+
+func TestEventsOrderingAndFilters(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(5)})
+	defer server.Close()
+	client := server.Client()
+
+	ascending, err := client.GetEventsByPage(1, 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, ids(ascending.Events))
+
+	descending, err := client.GetEventsByPage(0, 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5", "4", "3", "2", "1"}, ids(descending.Events))
+
+	active, err := client.GetActiveEventsByPage(0, 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3", "5"}, ids(active.Events))
+
+	var byUpdated []polymarket_gamma.Event
+	getJSON(t, server, "/events?order=updatedAt&ascending=false&limit=2", &byUpdated)
+	assert.Equal(t, []string{"1", "2"}, ids(byUpdated))
+
+	var bySlug []polymarket_gamma.Event
+	getJSON(t, server, "/events?slug=event-3&tag_slug=all", &bySlug)
+	assert.Equal(t, []string{"3"}, ids(bySlug))
+
+	var errorBody map[string]string
+	status := getJSON(t, server, "/events?order=nonsense", &errorBody)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestEventsOffsetAndLimitCaps(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(30), MaxOffset: 20, MaxLimit: 10})
+	defer server.Close()
+	client := server.Client()
+
+	page, err := client.GetEventsByPage(0, 25, true)
+	require.NoError(t, err)
+	assert.Len(t, page.Events, 10, "limit is silently capped")
+
+	_, err = client.GetEventsByPage(21, 10, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "422")
+}
+
+func TestEventsKeyset(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(25), MaxLimit: 10})
+	defer server.Close()
+	client := server.Client()
+
+	var all []string
+	cursor := ""
+	pages := 0
+	for {
+		page, err := client.GetEventsByKeysetPage(cursor, 50)
+		require.NoError(t, err)
+		all = append(all, ids(page.Events)...)
+		pages++
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Len(t, all, 25)
+	assert.Equal(t, "1", all[0])
+	assert.Equal(t, "25", all[24])
+
+	active, err := client.GetActiveEventsByKeysetPage("", 100)
+	require.NoError(t, err)
+	assert.Len(t, active.Events, 10)
+	assert.NotEmpty(t, active.NextCursor)
+
+	_, err = client.GetEventsByKeysetPage("not-a-cursor", 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+}
+
+func TestMarketsTagsAndSeries(t *testing.T) {
+	server := NewServer(&Config{
+		Events:  seedEvents(3),
+		Markets: []polymarket_gamma.Market{{ID: "standalone", Slug: "standalone-market"}},
+		Tags:    []polymarket_gamma.Tag{{ID: "1", Slug: "all"}, {ID: "2", Slug: "sports"}},
+		Series:  []polymarket_gamma.Series{{ID: "1", Slug: "nfl"}, {ID: "2", Slug: "nba"}},
+	})
+	defer server.Close()
+
+	var markets []polymarket_gamma.Market
+	getJSON(t, server, "/markets?ascending=true", &markets)
+	require.Len(t, markets, 4)
+
+	getJSON(t, server, "/markets?condition_ids=0xc2", &markets)
+	require.Len(t, markets, 1)
+	assert.Equal(t, "m2", markets[0].ID)
+
+	getJSON(t, server, "/markets?clob_token_ids=t3b", &markets)
+	require.Len(t, markets, 1)
+	assert.Equal(t, "m3", markets[0].ID)
+
+	var market polymarket_gamma.Market
+	assert.Equal(t, http.StatusOK, getJSON(t, server, "/markets/standalone", &market))
+	assert.Equal(t, "standalone-market", market.Slug)
+
+	var event polymarket_gamma.Event
+	assert.Equal(t, http.StatusOK, getJSON(t, server, "/events/2", &event))
+	assert.Equal(t, "Event 2", event.Title)
+	var missing map[string]string
+	assert.Equal(t, http.StatusNotFound, getJSON(t, server, "/events/99", &missing))
+
+	var tags []polymarket_gamma.Tag
+	getJSON(t, server, "/tags?limit=1&offset=1", &tags)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "sports", tags[0].Slug)
+
+	var series []polymarket_gamma.Series
+	getJSON(t, server, "/series?slug=nba", &series)
+	require.Len(t, series, 1)
+	assert.Equal(t, "2", series[0].ID)
+}
+
+func TestClientQueries(t *testing.T) {
+	server := NewServer(&Config{
+		Events: seedEvents(5),
+		Markets: []polymarket_gamma.Market{
+			{ID: "101", Slug: "first", ConditionID: "0xa"},
+			{ID: "102", Slug: "second", ConditionID: "0xb", Closed: true},
+		},
+		Tags:   []polymarket_gamma.Tag{{ID: "1", Label: "All", Slug: "all"}, {ID: "2", Label: "Sports", Slug: "sports"}},
+		Series: []polymarket_gamma.Series{{ID: "1", Slug: "nfl"}, {ID: "2", Slug: "nba"}},
+	})
+	defer server.Close()
+	client := server.Client()
+
+	closed := false
+	events, err := client.QueryEvents(&polymarket_gamma.EventQuery{
+		Limit:     2,
+		Order:     "volume",
+		Ascending: false,
+		TagSlug:   "all",
+		Closed:    &closed,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5", "3"}, ids(events.Events))
+
+	markets, err := client.GetMarketsByIDs([]int{102, 101, 102, 999})
+	require.NoError(t, err)
+	require.Len(t, markets.Markets, 2)
+	assert.Equal(t, "second", markets.Markets[0].Slug)
+	assert.Equal(t, "first", markets.Markets[1].Slug)
+	assert.Equal(t, []int{999}, markets.MissingIDs)
+
+	markets, err = client.QueryMarkets(&polymarket_gamma.MarketQuery{ConditionIDs: []string{"0xc4"}})
+	require.NoError(t, err)
+	require.Len(t, markets.Markets, 1)
+	assert.Equal(t, "m4", markets.Markets[0].ID)
+
+	tags, err := client.GetTagsByPage(1, 10)
+	require.NoError(t, err)
+	require.Len(t, tags.Tags, 1)
+	assert.Equal(t, "sports", tags.Tags[0].Slug)
+
+	series, err := client.GetSeriesByPage(0, 10)
+	require.NoError(t, err)
+	assert.Len(t, series.Series, 2)
+
+	search, err := client.Search("EVENT 4", 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4"}, ids(search.Events))
+	assert.Empty(t, search.Tags)
+	assert.False(t, search.Pagination.HasMore)
+
+	search, err = client.Search("sport", 0)
+	require.NoError(t, err)
+	assert.Empty(t, search.Events)
+	require.Len(t, search.Tags, 1)
+	assert.Equal(t, "2", search.Tags[0].ID)
+
+	assert.Equal(t, []string{
+		"/events?ascending=false&closed=false&limit=2&order=volume&tag_slug=all",
+		"/markets?id=102&id=101&id=999&limit=3",
+		"/markets?condition_ids=0xc4",
+		"/tags?limit=10&offset=1",
+		"/series?limit=10&offset=0",
+		"/public-search?limit_per_type=5&q=EVENT+4",
+		"/public-search?q=sport",
+	}, server.Requests())
+}
+
+func TestRangeAndRelatedTagFilters(t *testing.T) {
+	events := seedEvents(5)
+	for i := range events {
+		n := 5 - i
+		events[i].Liquidity = float64(n * 100)
+		events[i].StartDate = time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC)
+		events[i].EndDate = time.Date(2026, 2, n, 0, 0, 0, 0, time.UTC)
+		events[i].Markets[0].LiquidityNum = float64(n)
+		events[i].Markets[0].VolumeNum = float64(n * 10)
+		events[i].Markets[0].EndDate = events[i].EndDate
+	}
+	events[0].Tags = []polymarket_gamma.Tag{{ID: "3", Slug: "nba"}}
+
+	server := NewServer(&Config{
+		Events:      events,
+		RelatedTags: map[string][]string{"2": {"3"}},
+	})
+	defer server.Close()
+	client := server.Client()
+
+	response, err := client.QueryEvents(&polymarket_gamma.EventQuery{
+		Ascending:    true,
+		Order:        "id",
+		LiquidityMin: 200,
+		VolumeMax:    40,
+		StartDateMax: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3", "4"}, ids(response.Events))
+
+	response, err = client.QueryEvents(&polymarket_gamma.EventQuery{
+		EndDateMin: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+		EndDateMax: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"2", "3"}, ids(response.Events))
+
+	response, err = client.QueryEvents(&polymarket_gamma.EventQuery{TagID: 2})
+	require.NoError(t, err)
+	assert.Empty(t, response.Events)
+
+	response, err = client.QueryEvents(&polymarket_gamma.EventQuery{TagID: 2, RelatedTags: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5"}, ids(response.Events))
+
+	markets, err := client.QueryMarkets(&polymarket_gamma.MarketQuery{
+		LiquidityMin: 2,
+		LiquidityMax: 4,
+		VolumeMin:    30,
+		EndDateMax:   time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, markets.Markets, 2)
+	assert.ElementsMatch(t, []string{"m3", "m4"}, []string{markets.Markets[0].ID, markets.Markets[1].ID})
+
+	var errorBody map[string]string
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, server, "/events?liquidity_min=many", &errorBody))
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, server, "/events?spread_max=1", &errorBody))
+	assert.Contains(t, errorBody["error"], `unsupported parameter "spread_max"`)
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, server, "/markets?volume_min=1", &errorBody))
+}
+
+func TestAPIErrorNamesEndpoint(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+
+	_, err := server.Client().GetTagsByPage(-1, 10)
+	var apiErr *polymarket_gamma.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "/tags", apiErr.Endpoint)
+	assert.ErrorContains(t, err, "failed to fetch /tags: 400")
+}
+
+type recordingArchiver struct {
+	records []*polymarket_gamma.ArchiveRecord
+}
+
+func (a *recordingArchiver) Archive(record *polymarket_gamma.ArchiveRecord) error {
+	a.records = append(a.records, record)
+	return nil
+}
+
+func TestGzippedErrorBodiesAreDecoded(t *testing.T) {
+	server := NewServer(nil)
+	defer server.Close()
+
+	archiver := &recordingArchiver{}
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL:  server.URL,
+		Archiver: archiver,
+	})
+
+	_, err := client.GetEventsByPage(-1, 10, true)
+	var apiErr *polymarket_gamma.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Contains(t, string(apiErr.Body), "invalid offset")
+	require.Len(t, archiver.records, 1)
+	assert.Equal(t, "gzip", archiver.records[0].Header.Get("Content-Encoding"))
+	assert.Contains(t, string(archiver.records[0].Body), "invalid offset")
+}
+
+func TestGzip(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(2)})
+	defer server.Close()
+
+	var meta *polymarket_gamma.ResponseMeta
+	client := server.Client()
+	response, err := client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+	meta = response.Meta
+	assert.Equal(t, "gzip", meta.Header.Get("Content-Encoding"))
+	assert.Less(t, meta.BytesReceived, meta.BytesDecoded)
+
+	// Plain clients get uncompressed JSON
+	var events []polymarket_gamma.Event
+	req, _ := http.NewRequest("GET", server.URL+"/events", nil)
+	req.Header.Set("Accept-Encoding", "identity")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+	assert.Len(t, events, 2)
+}
+
+func TestMutations(t *testing.T) {
+	server := NewServer(&Config{Events: seedEvents(2)})
+	defer server.Close()
+	client := server.Client()
+
+	server.UpsertEvent(polymarket_gamma.Event{ID: "2", Title: "Updated"})
+	server.UpsertEvent(polymarket_gamma.Event{ID: "3", Title: "New"})
+	server.RemoveEvent("1")
+
+	response, err := client.GetEventsByPage(0, 10, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2", "3"}, ids(response.Events))
+	assert.Equal(t, "Updated", response.Events[0].Title)
+
+	server.SetEvents(nil)
+	response, err = client.GetEventsByPage(0, 10, true)
+	require.NoError(t, err)
+	assert.Empty(t, response.Events)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id, err := DecodeCursor(EncodeCursor("12345"))
+	require.NoError(t, err)
+	assert.Equal(t, "12345", id)
+}
@@ -0,0 +1,179 @@
+// Package fields resolves struct fields by name, so rule and filter languages can
+// refer to Event and Market fields the way they appear in the API's JSON.
+package fields
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Field is a resolved struct field
+type Field struct {
+	// Name is the field's JSON name
+	Name  string
+	Index []int
+	Type  reflect.Type
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	cache    sync.Map // reflect.Type -> []Field
+)
+
+// Get returns the field's value in v, which must be a struct of the type the field
+// was resolved on
+func (f Field) Get(v reflect.Value) reflect.Value {
+	return v.FieldByIndex(f.Index)
+}
+
+// Lookup resolves name on the struct type t. Names match the JSON name exactly or,
+// failing that, the JSON or Go name ignoring case, so "bestAsk", "BestAsk" and
+// "bestask" are the same field. The error for an unknown name suggests the closest
+// field.
+func Lookup(t reflect.Type, name string) (Field, error) {
+	all := fieldsOf(t)
+	for _, field := range all {
+		if field.Name == name {
+			return field, nil
+		}
+	}
+	for _, field := range all {
+		if strings.EqualFold(field.Name, name) || strings.EqualFold(t.FieldByIndex(field.Index).Name, name) {
+			return field, nil
+		}
+	}
+
+	if suggestion := closest(all, name); suggestion != "" {
+		return Field{}, fmt.Errorf("unknown field %q on %s (did you mean %q?)", name, t.Name(), suggestion)
+	}
+	return Field{}, fmt.Errorf("unknown field %q on %s", name, t.Name())
+}
+
+// Names returns the JSON names of t's fields, sorted
+func Names(t reflect.Type) []string {
+	var names []string
+	for _, field := range fieldsOf(t) {
+		names = append(names, field.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsTime reports whether t is time.Time
+func IsTime(t reflect.Type) bool {
+	return t == timeType
+}
+
+// IsNumeric reports whether values of t convert to numbers. Strings count, since the
+// API returns some amounts, such as Market.Volume, as decimal strings.
+func IsNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// Number converts v to a float64. Numeric strings are parsed; anything else fails.
+func Number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func fieldsOf(t reflect.Type) []Field {
+	if cached, ok := cache.Load(t); ok {
+		return cached.([]Field)
+	}
+
+	var all []Field
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		all = append(all, Field{Name: name, Index: field.Index, Type: field.Type})
+	}
+
+	cache.Store(t, all)
+	return all
+}
+
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// closest returns the field name nearest to name by edit distance, or "" when
+// nothing is near enough to be a plausible typo
+func closest(all []Field, name string) string {
+	best, bestDistance := "", len(name)/3+2
+	for _, field := range all {
+		if d := distance(strings.ToLower(field.Name), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = field.Name, d
+		}
+	}
+	return best
+}
+
+// distance is the Levenshtein distance between a and b
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
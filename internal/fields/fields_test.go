@@ -0,0 +1,49 @@
+package fields
+
+import (
+	"reflect"
+	"testing"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var marketType = reflect.TypeOf(polymarket_gamma.Market{})
+
+func TestLookup(t *testing.T) {
+	market := polymarket_gamma.Market{BestAsk: 0.6, Volume: "1200.5"}
+
+	for _, name := range []string{"bestAsk", "BestAsk", "bestask"} {
+		field, err := Lookup(marketType, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, "bestAsk", field.Name)
+		assert.Equal(t, 0.6, field.Get(reflect.ValueOf(market)).Interface())
+	}
+
+	field, err := Lookup(marketType, "volume")
+	require.NoError(t, err)
+	assert.True(t, IsNumeric(field.Type))
+	volume, ok := Number(field.Get(reflect.ValueOf(market)).Interface())
+	assert.True(t, ok)
+	assert.Equal(t, 1200.5, volume)
+
+	_, err = Lookup(marketType, "bestAks")
+	assert.EqualError(t, err, `unknown field "bestAks" on Market (did you mean "bestAsk"?)`)
+
+	_, err = Lookup(marketType, "nonsense")
+	assert.EqualError(t, err, `unknown field "nonsense" on Market`)
+}
+
+func TestNumber(t *testing.T) {
+	for _, v := range []any{3, int64(3), 3.0, float32(3), "3", " 3 "} {
+		n, ok := Number(v)
+		assert.True(t, ok, "%#v", v)
+		assert.Equal(t, 3.0, n)
+	}
+
+	for _, v := range []any{"three", true, nil} {
+		_, ok := Number(v)
+		assert.False(t, ok, "%#v", v)
+	}
+}
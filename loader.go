@@ -0,0 +1,213 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLoaderWait     = 2 * time.Millisecond
+	defaultLoaderMaxBatch = 100
+)
+
+// ErrEventNotFound is returned by EventLoader.Load when the API did not return the
+// requested event
+var ErrEventNotFound = errors.New("event not found")
+
+type LoaderConfig struct {
+	// Wait is how long Load calls are collected before a batch is sent (default 2ms)
+	Wait time.Duration
+	// MaxBatch sends a batch as soon as it holds this many IDs (default 100)
+	MaxBatch int
+}
+
+// EventLoader collects individual event lookups over a short window and resolves
+// them with a single GetEventsByIDs call. Results are memoised for the lifetime of
+// the loader, so a loader is usually created per incoming request; failed lookups
+// are not memoised.
+type EventLoader struct {
+	api      EventsAPI
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	results map[int]*loaderResult
+	batch   *loaderBatch
+}
+
+type loaderResult struct {
+	done  chan struct{}
+	event *Event
+	err   error
+}
+
+type loaderBatch struct {
+	// ctx is the context of the first Load in the batch, detached from its
+	// cancellation so one caller giving up does not fail the others
+	ctx        context.Context
+	ids        []int
+	results    []*loaderResult
+	timer      *time.Timer
+	dispatched bool
+}
+
+func NewEventLoader(api EventsAPI, config *LoaderConfig) *EventLoader {
+	if config == nil {
+		config = &LoaderConfig{}
+	}
+
+	wait := config.Wait
+	if wait == 0 {
+		wait = defaultLoaderWait
+	}
+
+	maxBatch := config.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = defaultLoaderMaxBatch
+	}
+
+	return &EventLoader{
+		api:      api,
+		wait:     wait,
+		maxBatch: maxBatch,
+		results:  map[int]*loaderResult{},
+	}
+}
+
+// Load returns the event with the given ID, batching the lookup with any other
+// Load calls made within the loader's window. Callers loading the same ID share the
+// returned *Event and must not modify it.
+func (l *EventLoader) Load(ctx context.Context, id int) (*Event, error) {
+	result := l.enqueue(ctx, id)
+
+	select {
+	case <-result.done:
+		return result.event, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// LoadMany loads every ID in a single batch where possible. The returned events
+// and errors are index-aligned with ids.
+func (l *EventLoader) LoadMany(ctx context.Context, ids []int) ([]*Event, []error) {
+	results := make([]*loaderResult, len(ids))
+	for i, id := range ids {
+		results[i] = l.enqueue(ctx, id)
+	}
+
+	events := make([]*Event, len(ids))
+	errs := make([]error, len(ids))
+	for i, result := range results {
+		select {
+		case <-result.done:
+			events[i], errs[i] = result.event, result.err
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	return events, errs
+}
+
+// Prime stores event in the loader so later loads of its ID need no request
+func (l *EventLoader) Prime(event Event) {
+	id, err := strconv.Atoi(event.ID)
+	if err != nil {
+		return
+	}
+
+	result := &loaderResult{done: make(chan struct{}), event: &event}
+	close(result.done)
+
+	l.mu.Lock()
+	l.results[id] = result
+	l.mu.Unlock()
+}
+
+// Clear forgets the memoised result for id
+func (l *EventLoader) Clear(id int) {
+	l.mu.Lock()
+	delete(l.results, id)
+	l.mu.Unlock()
+}
+
+func (l *EventLoader) enqueue(ctx context.Context, id int) *loaderResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if result, ok := l.results[id]; ok {
+		return result
+	}
+
+	result := &loaderResult{done: make(chan struct{})}
+	l.results[id] = result
+
+	if l.batch == nil {
+		batch := &loaderBatch{ctx: context.WithoutCancel(ctx)}
+		batch.timer = time.AfterFunc(l.wait, func() { l.dispatch(batch) })
+		l.batch = batch
+	}
+
+	l.batch.ids = append(l.batch.ids, id)
+	l.batch.results = append(l.batch.results, result)
+	if len(l.batch.ids) >= l.maxBatch {
+		batch := l.batch
+		batch.timer.Stop()
+		l.batch = nil
+		go l.dispatch(batch)
+	}
+
+	return result
+}
+
+// dispatch fetches batch and resolves every pending result in it
+func (l *EventLoader) dispatch(batch *loaderBatch) {
+	l.mu.Lock()
+	if batch.dispatched {
+		l.mu.Unlock()
+		return
+	}
+	batch.dispatched = true
+	if l.batch == batch {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	response, err := l.api.GetEventsByIDsContext(batch.ctx, batch.ids)
+	if err != nil {
+		err = fmt.Errorf("failed to load events: %w", err)
+	}
+
+	found := map[int]*Event{}
+	if response != nil {
+		for i := range response.Events {
+			if id, convErr := strconv.Atoi(response.Events[i].ID); convErr == nil {
+				found[id] = &response.Events[i]
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, id := range batch.ids {
+		result := batch.results[i]
+		switch {
+		case err != nil:
+			result.err = err
+		case found[id] != nil:
+			result.event = found[id]
+		default:
+			result.err = fmt.Errorf("event %d: %w", id, ErrEventNotFound)
+		}
+
+		if result.err != nil && l.results[id] == result {
+			delete(l.results, id)
+		}
+		close(result.done)
+	}
+}
@@ -0,0 +1,126 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder is an EventsAPI that records each GetEventsByIDs batch and returns
+// an event for every ID not in missing
+type batchRecorder struct {
+	EventsAPI
+
+	mu      sync.Mutex
+	batches [][]int
+	missing map[int]bool
+	err     error
+}
+
+func (b *batchRecorder) GetEventsByIDsContext(ctx context.Context, ids []int) (*GetEventsResponse, error) {
+	b.mu.Lock()
+	b.batches = append(b.batches, append([]int(nil), ids...))
+	b.mu.Unlock()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	response := &GetEventsResponse{}
+	for _, id := range ids {
+		if !b.missing[id] {
+			response.Events = append(response.Events, mockEvent(strconv.Itoa(id)))
+		}
+	}
+	return response, nil
+}
+
+func (b *batchRecorder) Batches() [][]int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.batches
+}
+
+func TestEventLoaderBatchesConcurrentLoads(t *testing.T) {
+	api := &batchRecorder{missing: map[int]bool{3: true}}
+	loader := NewEventLoader(api, &LoaderConfig{Wait: 20 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for _, id := range []int{1, 2, 3, 1} {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			event, err := loader.Load(context.Background(), id)
+			if id == 3 {
+				assert.ErrorIs(t, err, ErrEventNotFound)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, strconv.Itoa(id), event.ID)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	require.Len(t, api.Batches(), 1)
+	assert.ElementsMatch(t, []int{1, 2, 3}, api.Batches()[0])
+
+	// Found events are memoised, missing ones are not
+	_, err := loader.Load(context.Background(), 1)
+	require.NoError(t, err)
+	_, err = loader.Load(context.Background(), 3)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+	require.Len(t, api.Batches(), 2)
+	assert.Equal(t, []int{3}, api.Batches()[1])
+}
+
+func TestEventLoaderMaxBatch(t *testing.T) {
+	api := &batchRecorder{}
+	loader := NewEventLoader(api, &LoaderConfig{Wait: time.Hour, MaxBatch: 2})
+
+	events, errs := loader.LoadMany(context.Background(), []int{1, 2, 3, 4})
+	for i, err := range errs {
+		require.NoError(t, err)
+		assert.Equal(t, strconv.Itoa(i+1), events[i].ID)
+	}
+	assert.Len(t, api.Batches(), 2)
+}
+
+func TestEventLoaderPrimeAndClear(t *testing.T) {
+	api := &batchRecorder{}
+	loader := NewEventLoader(api, nil)
+
+	primed := mockEvent("5")
+	primed.Title = "primed"
+	loader.Prime(primed)
+
+	event, err := loader.Load(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, "primed", event.Title)
+	assert.Empty(t, api.Batches())
+
+	loader.Clear(5)
+	event, err = loader.Load(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Event", event.Title)
+	assert.Len(t, api.Batches(), 1)
+}
+
+func TestEventLoaderErrors(t *testing.T) {
+	api := &batchRecorder{err: errors.New("boom")}
+	loader := NewEventLoader(api, nil)
+
+	_, err := loader.Load(context.Background(), 1)
+	assert.ErrorContains(t, err, "failed to load events: boom")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewEventLoader(api, &LoaderConfig{Wait: time.Hour}).Load(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
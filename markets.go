@@ -0,0 +1,53 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"net/url"
+)
+
+// GetMarketsByIDs fetches markets by their IDs from the Polymarket Gamma API. Like
+// GetEventsByIDs, repeated IDs are fetched once, large ID lists are split into
+// concurrent chunks, markets are returned in the order of ids with the IDs the API did
+// not return listed in MissingIDs, and an empty ids makes no request.
+func (c *Client) GetMarketsByIDs(ids []int) (*GetMarketsResponse, error) {
+	return c.GetMarketsByIDsContext(context.Background(), ids)
+}
+
+// GetMarketsByIDsContext is GetMarketsByIDs with a caller-supplied context
+func (c *Client) GetMarketsByIDsContext(ctx context.Context, ids []int) (*GetMarketsResponse, error) {
+	return c.getMarketsByIDChunks(ctx, ids)
+}
+
+// getMarketsByIDs fetches a single chunk of IDs in one request
+func (c *Client) getMarketsByIDs(ctx context.Context, ids []int) (*GetMarketsResponse, error) {
+	return c.QueryMarketsContext(ctx, &MarketQuery{IDs: ids, Limit: len(ids)})
+}
+
+// QueryMarkets fetches the page of markets selected by query. It is offset paginated,
+// so the offset cap applies.
+func (c *Client) QueryMarkets(query *MarketQuery) (*GetMarketsResponse, error) {
+	return c.QueryMarketsContext(context.Background(), query)
+}
+
+// QueryMarketsContext is QueryMarkets with a caller-supplied context
+func (c *Client) QueryMarketsContext(ctx context.Context, query *MarketQuery) (*GetMarketsResponse, error) {
+	if query == nil {
+		query = &MarketQuery{}
+	}
+	return c.getMarkets(ctx, query.values())
+}
+
+func (c *Client) getMarkets(ctx context.Context, queryParams url.Values) (*GetMarketsResponse, error) {
+	markets, info, body, err := getJSON(ctx, c, "/markets", queryParams, func(info *RequestInfo, markets *[]Market) error {
+		return c.validateMarkets(info, *markets)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetMarketsResponse{
+		Markets: *markets,
+		Meta:    info.meta(),
+		Raw:     c.rawBody(body),
+	}, nil
+}
@@ -0,0 +1,160 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrorClass classifies why a request failed
+type ErrorClass string
+
+const (
+	// ErrorClassNone is used for requests that succeeded
+	ErrorClassNone ErrorClass = ""
+	// ErrorClassTransport covers failures to build or send the request
+	ErrorClassTransport ErrorClass = "transport"
+	// ErrorClassHTTP covers non-200 responses
+	ErrorClassHTTP ErrorClass = "http"
+	// ErrorClassDecode covers gzip, read and JSON parse failures
+	ErrorClassDecode ErrorClass = "parse"
+	// ErrorClassValidation covers responses that failed struct validation
+	ErrorClassValidation ErrorClass = "validation"
+	// ErrorClassArchive covers failures of the configured Archiver
+	ErrorClassArchive ErrorClass = "archive"
+)
+
+// RequestInfo describes a single request to the Gamma API. Fields are filled in as
+// the request progresses, so a failed request only carries what was known at the
+// point of failure.
+type RequestInfo struct {
+	// Endpoint is the API path, e.g. "/events" or "/events/keyset"
+	Endpoint string
+	Query    url.Values
+	// URL is the full request URL
+	URL string
+	// Start is when the client began handling the call
+	Start time.Time
+	// StatusCode is zero if no response was received
+	StatusCode int
+	// Header holds the response headers, if a response was received
+	Header http.Header
+	// FinalURL is the request URL after any redirects
+	FinalURL string
+	// CacheStatus says how the response cache handled the request, if enabled
+	CacheStatus CacheStatus
+	// Coalesced is true when the call shared another in-flight request's response
+	Coalesced bool
+	// Latency is the time from sending the request to reading the full body
+	Latency time.Duration
+	// Duration is the total time spent, including decoding and validation
+	Duration time.Duration
+	// BytesReceived is the size of the body on the wire (before gzip)
+	BytesReceived int64
+	// BytesDecoded is the size of the body after gzip decompression
+	BytesDecoded       int64
+	DecodeDuration     time.Duration
+	ValidationDuration time.Duration
+	// Events is the number of events decoded from the response
+	Events     int
+	ErrorClass ErrorClass
+	Err        error
+}
+
+// meta returns the transport details recorded so far as a ResponseMeta
+func (r *RequestInfo) meta() *ResponseMeta {
+	return &ResponseMeta{
+		StatusCode:    r.StatusCode,
+		Header:        r.Header,
+		URL:           r.FinalURL,
+		Latency:       r.Latency,
+		BytesReceived: r.BytesReceived,
+		BytesDecoded:  r.BytesDecoded,
+		CacheStatus:   r.CacheStatus,
+		Coalesced:     r.Coalesced,
+	}
+}
+
+// Observer receives a RequestInfo for every request made by a Client, after the
+// request has completed (successfully or not). ObserveRequest is called on the
+// caller's goroutine with the context passed to the client method, so it must be
+// safe for concurrent use and should return quickly.
+type Observer interface {
+	ObserveRequest(ctx context.Context, info *RequestInfo)
+}
+
+// RequestStarter is implemented by observers that need to act before a request is
+// made, such as tracers. StartRequest is called when a client method begins; the
+// context it returns is used for the call, including the HTTP request, and is the one
+// later passed to ObserveRequest.
+type RequestStarter interface {
+	StartRequest(ctx context.Context, info *RequestInfo) context.Context
+}
+
+// HeaderInjector is implemented by observers that propagate context to the API, such
+// as tracers injecting trace headers. InjectHeader is called with every outgoing HTTP
+// request's context and headers.
+type HeaderInjector interface {
+	InjectHeader(ctx context.Context, header http.Header)
+}
+
+// ObserverFunc adapts a function to the Observer interface
+type ObserverFunc func(ctx context.Context, info *RequestInfo)
+
+func (f ObserverFunc) ObserveRequest(ctx context.Context, info *RequestInfo) {
+	f(ctx, info)
+}
+
+func (c *Client) newRequestInfo(endpoint string, queryParams url.Values) *RequestInfo {
+	apiURL := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+	if len(queryParams) > 0 {
+		apiURL = fmt.Sprintf("%s?%s", apiURL, queryParams.Encode())
+	}
+
+	return &RequestInfo{
+		Endpoint: endpoint,
+		Query:    queryParams,
+		URL:      apiURL,
+		Start:    time.Now(),
+	}
+}
+
+// MultiObserver returns an Observer that forwards every request to each of the given
+// observers in order. Nil observers are skipped. StartRequest and InjectHeader are
+// forwarded to the observers implementing them.
+func MultiObserver(observers ...Observer) Observer {
+	var filtered multiObserver
+	for _, o := range observers {
+		if o != nil {
+			filtered = append(filtered, o)
+		}
+	}
+	return filtered
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) ObserveRequest(ctx context.Context, info *RequestInfo) {
+	for _, o := range m {
+		o.ObserveRequest(ctx, info)
+	}
+}
+
+func (m multiObserver) StartRequest(ctx context.Context, info *RequestInfo) context.Context {
+	for _, o := range m {
+		if starter, ok := o.(RequestStarter); ok {
+			ctx = starter.StartRequest(ctx, info)
+		}
+	}
+	return ctx
+}
+
+func (m multiObserver) InjectHeader(ctx context.Context, header http.Header) {
+	for _, o := range m {
+		if injector, ok := o.(HeaderInjector); ok {
+			injector.InjectHeader(ctx, header)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package otelgamma instruments a polymarket_gamma.Client with OpenTelemetry.
+//
+// Every API call is recorded as a client span carrying the endpoint, query, status,
+// response sizes and decode/validation timings, and counted in a set of metrics.
+// The span is started before the request is sent and its trace context is injected
+// into the request headers, so the API and any HTTP instrumentation below the client
+// see the same trace. It lives in its own package so the core client does not depend
+// on OpenTelemetry.
+//
+//	observer, err := otelgamma.NewObserver(nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+//	    Observer: observer,
+//	})
+//
+// Use the ...Context client methods so spans are parented to the caller's trace.
+package otelgamma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/CalderWhite/polymarket-gamma-go/otelgamma"
+
+// Config configures an Observer. The zero value uses the global OpenTelemetry
+// providers and propagator.
+type Config struct {
+	// TracerProvider defaults to the global provider
+	TracerProvider trace.TracerProvider
+	// MeterProvider defaults to the global provider
+	MeterProvider metric.MeterProvider
+	// Propagator injects trace context into request headers (default the global
+	// propagator)
+	Propagator propagation.TextMapPropagator
+}
+
+// Observer implements polymarket_gamma.Observer by emitting spans and metrics
+type Observer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	requests           metric.Int64Counter
+	duration           metric.Float64Histogram
+	eventsDecoded      metric.Int64Counter
+	validationFailures metric.Int64Counter
+}
+
+var (
+	_ polymarket_gamma.Observer       = (*Observer)(nil)
+	_ polymarket_gamma.RequestStarter = (*Observer)(nil)
+	_ polymarket_gamma.HeaderInjector = (*Observer)(nil)
+)
+
+// spanKey is the context key for the span started by StartRequest
+type spanKey struct{}
+
+// NewObserver creates the Observer's instruments. A nil config uses the global
+// providers and propagator.
+func NewObserver(config *Config) (*Observer, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	tracerProvider := config.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+
+	meterProvider := config.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+	meter := meterProvider.Meter(instrumentationName)
+
+	propagator := config.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	o := &Observer{
+		tracer:     tracerProvider.Tracer(instrumentationName),
+		propagator: propagator,
+	}
+
+	var err error
+	if o.requests, err = meter.Int64Counter("gamma.client.requests",
+		metric.WithDescription("Number of requests made to the Gamma API"),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create requests counter: %w", err)
+	}
+	if o.duration, err = meter.Float64Histogram("gamma.client.request.duration",
+		metric.WithDescription("Time taken by Gamma API calls, including decoding and validation"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create duration histogram: %w", err)
+	}
+	if o.eventsDecoded, err = meter.Int64Counter("gamma.client.events.decoded",
+		metric.WithDescription("Number of events decoded from Gamma API responses"),
+		metric.WithUnit("{event}"),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create events counter: %w", err)
+	}
+	if o.validationFailures, err = meter.Int64Counter("gamma.client.validation.failures",
+		metric.WithDescription("Number of Gamma API responses that failed validation"),
+		metric.WithUnit("{response}"),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create validation failures counter: %w", err)
+	}
+
+	return o, nil
+}
+
+// StartRequest starts the call's span as a child of the span in ctx
+func (o *Observer) StartRequest(ctx context.Context, info *polymarket_gamma.RequestInfo) context.Context {
+	ctx, span := o.tracer.Start(ctx, "GET "+info.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(info.Start),
+	)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// InjectHeader injects the trace context of ctx into an outgoing request's headers
+func (o *Observer) InjectHeader(ctx context.Context, header http.Header) {
+	o.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// ObserveRequest finishes the span started by StartRequest and updates the metrics.
+// Called without StartRequest, e.g. from a custom Observer wrapper, it records a span
+// back-dated to info.Start instead.
+func (o *Observer) ObserveRequest(ctx context.Context, info *polymarket_gamma.RequestInfo) {
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		_, span = o.tracer.Start(ctx, "GET "+info.Endpoint,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(info.Start),
+		)
+	}
+
+	span.SetAttributes(
+		attribute.String("http.request.method", "GET"),
+		attribute.String("url.full", info.URL),
+		attribute.String("gamma.endpoint", info.Endpoint),
+		attribute.String("gamma.query", info.Query.Encode()),
+		attribute.Int64("gamma.response.size", info.BytesDecoded),
+		attribute.Int64("gamma.response.compressed_size", info.BytesReceived),
+		attribute.Float64("gamma.latency_ms", milliseconds(info.Latency)),
+		attribute.Float64("gamma.decode_ms", milliseconds(info.DecodeDuration)),
+		attribute.Float64("gamma.validation_ms", milliseconds(info.ValidationDuration)),
+		attribute.Int("gamma.events", info.Events),
+	)
+	if info.StatusCode != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", info.StatusCode))
+	}
+	if info.Err != nil {
+		span.SetAttributes(attribute.String("error.type", string(info.ErrorClass)))
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+	}
+	span.End(trace.WithTimestamp(info.Start.Add(info.Duration)))
+
+	attrs := metric.WithAttributes(
+		attribute.String("gamma.endpoint", info.Endpoint),
+		attribute.Int("http.response.status_code", info.StatusCode),
+		attribute.String("error.type", string(info.ErrorClass)),
+	)
+	endpointAttrs := metric.WithAttributes(attribute.String("gamma.endpoint", info.Endpoint))
+
+	o.requests.Add(ctx, 1, attrs)
+	o.duration.Record(ctx, info.Duration.Seconds(), attrs)
+	if info.Events > 0 {
+		o.eventsDecoded.Add(ctx, int64(info.Events), endpointAttrs)
+	}
+	if info.ErrorClass == polymarket_gamma.ErrorClassValidation {
+		o.validationFailures.Add(ctx, 1, endpointAttrs)
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
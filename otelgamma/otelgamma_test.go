@@ -0,0 +1,181 @@
+package otelgamma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*polymarket_gamma.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	observer, err := NewObserver(&Config{
+		TracerProvider: tracerProvider,
+		MeterProvider:  meterProvider,
+	})
+	require.NoError(t, err)
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL:  server.URL,
+		Observer: observer,
+	})
+
+	return client, exporter, reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+func sum(m metricdata.Metrics) int64 {
+	var total int64
+	for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+		total += dp.Value
+	}
+	return total
+}
+
+func TestObserverRecordsSpan(t *testing.T) {
+	client, exporter, reader := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(polymarket_gamma.GetEventsKeysetResponse{
+			Events: []polymarket_gamma.Event{{ID: "1"}, {ID: "2"}},
+		})
+	})
+
+	_, err := client.GetEventsByKeysetPage("", 50)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, "GET /events/keyset", span.Name)
+	assert.Equal(t, codes.Unset, span.Status.Code)
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "/events/keyset", attrs["gamma.endpoint"].AsString())
+	assert.Equal(t, "limit=50", attrs["gamma.query"].AsString())
+	assert.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(2), attrs["gamma.events"].AsInt64())
+	assert.Positive(t, attrs["gamma.response.size"].AsInt64())
+	assert.Contains(t, attrs, attribute.Key("gamma.decode_ms"))
+	assert.Contains(t, attrs, attribute.Key("gamma.validation_ms"))
+
+	metrics := collect(t, reader)
+	assert.Equal(t, int64(1), sum(metrics["gamma.client.requests"]))
+	assert.Equal(t, int64(2), sum(metrics["gamma.client.events.decoded"]))
+	assert.Contains(t, metrics, "gamma.client.request.duration")
+	assert.NotContains(t, metrics, "gamma.client.validation.failures")
+}
+
+func TestObserverRecordsValidationFailure(t *testing.T) {
+	client, exporter, reader := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"slug": "no-id"}]`))
+	})
+
+	_, err := client.GetEventsByIDs([]int{1})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+
+	metrics := collect(t, reader)
+	assert.Equal(t, int64(1), sum(metrics["gamma.client.validation.failures"]))
+}
+
+func TestObserverParentsSpanToContext(t *testing.T) {
+	client, exporter, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, err := client.GetEventsByPageContext(ctx, 0, 10, true)
+	require.NoError(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "GET /events", spans[0].Name)
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext.TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestObserverPropagatesTraceContext(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	observer, err := NewObserver(&Config{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		MeterProvider:  sdkmetric.NewMeterProvider(),
+		Propagator:     propagation.TraceContext{},
+	})
+	require.NoError(t, err)
+
+	// A transport below the client sees the call's span on the request context, as
+	// HTTP instrumentation would
+	var transportSpan trace.SpanContext
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL:  server.URL,
+		Observer: polymarket_gamma.MultiObserver(observer),
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			transportSpan = trace.SpanContextFromContext(r.Context())
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	})
+
+	_, err = client.GetEventsByPage(0, 10, true)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0].SpanContext
+	assert.Equal(t, span.SpanID(), transportSpan.SpanID())
+	assert.Equal(t, "00-"+span.TraceID().String()+"-"+span.SpanID().String()+"-01", traceparent)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,186 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"strconv"
+)
+
+// maxPageSize is the most rows the API returns per page; larger limits are silently
+// truncated
+const maxPageSize = 100
+
+// PageMode is the pagination strategy a Page was fetched with
+type PageMode string
+
+const (
+	PageModeOffset PageMode = "offset"
+	PageModeKeyset PageMode = "keyset"
+)
+
+type PagerConfig struct {
+	// PageSize is the number of events requested per page (default and maximum 100)
+	PageSize int
+	// ActiveOnly pages through active events only
+	ActiveOnly bool
+	// OnWarning is called when the pager adjusts the request or switches to keyset
+	// pagination (optional)
+	OnWarning func(message string)
+}
+
+// Page is one page of events from a Pager
+type Page struct {
+	Events []Event
+	Mode   PageMode
+	// Offset is the offset the page was requested at, for offset pages
+	Offset int
+	// Cursor is the cursor the page was requested after, for keyset pages
+	Cursor string
+}
+
+// Pager iterates over every event in ascending ID order. It starts with offset
+// pagination and switches to keyset pagination once the API rejects the offset
+// (422 past the offset cap), so callers see one uniform sequence of pages either way.
+// A short offset page does not end the walk, because the API may cap pages below the
+// page size; the pager probes the next offset and stops at the first empty page.
+//
+// The keyset cursor is opaque and cannot be derived from an offset, so after
+// switching the pager walks keyset pages from the start and drops the events it
+// already returned. Past the cap that re-fetches the first ~2500 events once.
+type Pager struct {
+	api    EventsAPI
+	config PagerConfig
+}
+
+func NewPager(api EventsAPI, config *PagerConfig) *Pager {
+	if config == nil {
+		config = &PagerConfig{}
+	}
+
+	pager := &Pager{api: api, config: *config}
+	if pager.config.PageSize <= 0 {
+		pager.config.PageSize = maxPageSize
+	}
+	if pager.config.PageSize > maxPageSize {
+		pager.warn(fmt.Sprintf("page size %d exceeds the API's cap of %d rows, using %d", pager.config.PageSize, maxPageSize, maxPageSize))
+		pager.config.PageSize = maxPageSize
+	}
+	return pager
+}
+
+// Pages returns an iterator over every page. Iteration stops after the first error.
+func (p *Pager) Pages(ctx context.Context) iter.Seq2[*Page, error] {
+	return func(yield func(*Page, error) bool) {
+		lastID := 0
+		offset := 0
+		shortPage := 0
+
+		for {
+			response, err := p.fetchOffset(ctx, offset)
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+				p.warn(fmt.Sprintf("offset %d rejected by the API, switching to keyset pagination", offset))
+				break
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to fetch page at offset %d: %w", offset, err))
+				return
+			}
+			if len(response.Events) == 0 {
+				return
+			}
+			if shortPage > 0 {
+				p.warn(fmt.Sprintf("the API returned %d rows for a page size of %d, continuing at its row cap", shortPage, p.config.PageSize))
+				shortPage = -1
+			}
+
+			lastID = max(lastID, maxEventID(response.Events))
+			if !yield(&Page{Events: response.Events, Mode: PageModeOffset, Offset: offset}, nil) {
+				return
+			}
+
+			offset += len(response.Events)
+			if shortPage == 0 && len(response.Events) < p.config.PageSize {
+				shortPage = len(response.Events)
+			}
+		}
+
+		cursor := ""
+		for {
+			response, err := p.fetchKeyset(ctx, cursor)
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to fetch page after cursor %q: %w", cursor, err))
+				return
+			}
+
+			events := make([]Event, 0, len(response.Events))
+			for _, event := range response.Events {
+				if id, err := strconv.Atoi(event.ID); err != nil || id > lastID {
+					events = append(events, event)
+				}
+			}
+
+			if len(events) > 0 {
+				if !yield(&Page{Events: events, Mode: PageModeKeyset, Cursor: cursor}, nil) {
+					return
+				}
+			}
+
+			if response.NextCursor == "" {
+				return
+			}
+			cursor = response.NextCursor
+		}
+	}
+}
+
+// Events returns an iterator over every event, page by page
+func (p *Pager) Events(ctx context.Context) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		for page, err := range p.Pages(ctx) {
+			if err != nil {
+				yield(Event{}, err)
+				return
+			}
+			for _, event := range page.Events {
+				if !yield(event, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Pager) fetchOffset(ctx context.Context, offset int) (*GetEventsResponse, error) {
+	if p.config.ActiveOnly {
+		return p.api.GetActiveEventsByPageContext(ctx, offset, p.config.PageSize, true)
+	}
+	return p.api.GetEventsByPageContext(ctx, offset, p.config.PageSize, true)
+}
+
+func (p *Pager) fetchKeyset(ctx context.Context, cursor string) (*GetEventsKeysetResponse, error) {
+	if p.config.ActiveOnly {
+		return p.api.GetActiveEventsByKeysetPageContext(ctx, cursor, p.config.PageSize)
+	}
+	return p.api.GetEventsByKeysetPageContext(ctx, cursor, p.config.PageSize)
+}
+
+func (p *Pager) warn(message string) {
+	if p.config.OnWarning != nil {
+		p.config.OnWarning(message)
+	}
+}
+
+// maxEventID returns the largest numeric event ID in events
+func maxEventID(events []Event) int {
+	maxID := 0
+	for _, event := range events {
+		if id, err := strconv.Atoi(event.ID); err == nil {
+			maxID = max(maxID, id)
+		}
+	}
+	return maxID
+}
@@ -0,0 +1,143 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// offsetAPI adds offset pagination to keysetAPI, rejecting offsets past maxOffset
+// and truncating pages to rowCap rows
+type offsetAPI struct {
+	*keysetAPI
+
+	maxOffset int
+	rowCap    int
+	offsets   []int
+}
+
+func (o *offsetAPI) GetEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error) {
+	o.offsets = append(o.offsets, offset)
+	if offset > o.maxOffset {
+		return nil, &APIError{StatusCode: http.StatusUnprocessableEntity, Status: "422 Unprocessable Entity"}
+	}
+
+	response := &GetEventsResponse{Events: []Event{}}
+	for id := offset + 1; id <= o.total && len(response.Events) < min(limit, o.rowCap); id++ {
+		response.Events = append(response.Events, mockEvent(strconv.Itoa(id)))
+	}
+	return response, nil
+}
+
+func pagedIDs(t *testing.T, pager *Pager) ([]string, []PageMode) {
+	var ids []string
+	var modes []PageMode
+	for page, err := range pager.Pages(context.Background()) {
+		require.NoError(t, err)
+		modes = append(modes, page.Mode)
+		for _, event := range page.Events {
+			ids = append(ids, event.ID)
+		}
+	}
+	return ids, modes
+}
+
+func sequentialIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + 1)
+	}
+	return ids
+}
+
+func TestPagerFallsBackPastOffsetCap(t *testing.T) {
+	api := &offsetAPI{keysetAPI: &keysetAPI{total: 25}, maxOffset: 15, rowCap: 100}
+
+	var warnings []string
+	pager := NewPager(api, &PagerConfig{
+		PageSize:  10,
+		OnWarning: func(message string) { warnings = append(warnings, message) },
+	})
+
+	ids, modes := pagedIDs(t, pager)
+	assert.Equal(t, sequentialIDs(25), ids)
+	assert.Equal(t, []PageMode{PageModeOffset, PageModeOffset, PageModeKeyset}, modes)
+	assert.Equal(t, []int{0, 10, 20}, api.offsets)
+	assert.Equal(t, []string{"offset 20 rejected by the API, switching to keyset pagination"}, warnings)
+}
+
+func TestPagerProbesPastShortPage(t *testing.T) {
+	api := &offsetAPI{keysetAPI: &keysetAPI{total: 12}, maxOffset: 2500, rowCap: 100}
+
+	ids, modes := pagedIDs(t, NewPager(api, &PagerConfig{PageSize: 10}))
+	assert.Equal(t, sequentialIDs(12), ids)
+	assert.Equal(t, []PageMode{PageModeOffset, PageModeOffset}, modes)
+	assert.Equal(t, []int{0, 10, 12}, api.offsets)
+	assert.Empty(t, api.calls, "an empty offset page is the end, so keyset is never walked")
+}
+
+func TestPagerContinuesPastLoweredRowCap(t *testing.T) {
+	api := &offsetAPI{keysetAPI: &keysetAPI{total: 25}, maxOffset: 2500, rowCap: 4}
+
+	var warnings []string
+	pager := NewPager(api, &PagerConfig{
+		PageSize:  10,
+		OnWarning: func(message string) { warnings = append(warnings, message) },
+	})
+
+	ids, _ := pagedIDs(t, pager)
+	assert.Equal(t, sequentialIDs(25), ids)
+	assert.Equal(t, []int{0, 4, 8, 12, 16, 20, 24, 25}, api.offsets)
+	assert.Equal(t, []string{"the API returned 4 rows for a page size of 10, continuing at its row cap"}, warnings)
+}
+
+func TestPagerWarnsAboutPageSizeOverCap(t *testing.T) {
+	api := &offsetAPI{keysetAPI: &keysetAPI{total: 3}, maxOffset: 2500, rowCap: 100}
+
+	var warnings []string
+	pager := NewPager(api, &PagerConfig{
+		PageSize:  500,
+		OnWarning: func(message string) { warnings = append(warnings, message) },
+	})
+
+	ids, _ := pagedIDs(t, pager)
+	assert.Equal(t, sequentialIDs(3), ids)
+	assert.Equal(t, []string{"page size 500 exceeds the API's cap of 100 rows, using 100"}, warnings)
+}
+
+func TestPagerEventsStopsEarlyAndReportsErrors(t *testing.T) {
+	api := &offsetAPI{keysetAPI: &keysetAPI{total: 30}, maxOffset: 2500, rowCap: 100}
+
+	var ids []string
+	for event, err := range NewPager(api, &PagerConfig{PageSize: 10}).Events(context.Background()) {
+		require.NoError(t, err)
+		ids = append(ids, event.ID)
+		if len(ids) == 12 {
+			break
+		}
+	}
+	assert.Equal(t, sequentialIDs(12), ids)
+	assert.Equal(t, []int{0, 10}, api.offsets)
+
+	// Errors other than the offset cap are not retried through keyset
+	var lastErr error
+	for _, err := range NewPager(unavailableAPI{}, nil).Pages(context.Background()) {
+		lastErr = err
+	}
+	var apiErr *APIError
+	require.ErrorAs(t, lastErr, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.ErrorContains(t, lastErr, "failed to fetch page at offset 0: failed to fetch /events: 503")
+}
+
+type unavailableAPI struct {
+	EventsAPI
+}
+
+func (unavailableAPI) GetEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error) {
+	return nil, &APIError{Endpoint: "/events", StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+}
@@ -0,0 +1,170 @@
+// Package promgamma exports polymarket_gamma.Client activity as Prometheus metrics.
+//
+// WithMetrics wires a client up in one line, registering a Collector and adding it
+// to any Observer already on the config:
+//
+//	client := polymarket_gamma.NewClient(promgamma.WithMetrics(config, prometheus.DefaultRegisterer))
+//
+// A Collector is both a prometheus.Collector and a polymarket_gamma.Observer, so it
+// can also be registered and passed as ClientConfig.Observer by hand:
+//
+//	collector := promgamma.NewCollector(nil)
+//	prometheus.MustRegister(collector)
+//
+//	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+//	    Observer: collector,
+//	})
+package promgamma
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultNamespace = "gamma"
+
+type Config struct {
+	// Namespace prefixes every metric name (default "gamma")
+	Namespace string
+	// ConstLabels are added to every metric (optional)
+	ConstLabels prometheus.Labels
+	// Buckets for the request duration histogram (default prometheus.DefBuckets)
+	Buckets []float64
+}
+
+// Collector tracks requests, errors, latency and transfer sizes per endpoint
+type Collector struct {
+	requests      *prometheus.CounterVec
+	errors        *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	bytesReceived *prometheus.CounterVec
+	bytesDecoded  *prometheus.CounterVec
+	keysetPages   *prometheus.CounterVec
+}
+
+var (
+	_ prometheus.Collector      = (*Collector)(nil)
+	_ polymarket_gamma.Observer = (*Collector)(nil)
+)
+
+func NewCollector(config *Config) *Collector {
+	if config == nil {
+		config = &Config{}
+	}
+
+	namespace := config.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	buckets := config.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+
+	counter := func(name, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        name,
+			Help:        help,
+			ConstLabels: config.ConstLabels,
+		}, labels)
+	}
+
+	return &Collector{
+		requests: counter("requests_total",
+			"Requests made to the Gamma API.", "endpoint", "status_code"),
+		errors: counter("errors_total",
+			"Failed Gamma API requests by error class (transport, http, parse, validation).", "endpoint", "class", "status_code"),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "request_duration_seconds",
+			Help:        "Time taken by Gamma API calls, including decoding and validation.",
+			ConstLabels: config.ConstLabels,
+			Buckets:     buckets,
+		}, []string{"endpoint"}),
+		bytesReceived: counter("received_bytes_total",
+			"Response bytes received on the wire, before gzip decompression.", "endpoint"),
+		bytesDecoded: counter("decoded_bytes_total",
+			"Response bytes after gzip decompression.", "endpoint"),
+		keysetPages: counter("keyset_pages_total",
+			"Keyset pages successfully fetched from /events/keyset.", "endpoint"),
+	}
+}
+
+// WithMetrics returns a copy of config whose Observer also feeds a Collector with
+// the default Config, registered with registerer (default
+// prometheus.DefaultRegisterer). Clients wired up against the same registerer share
+// one Collector. Like prometheus.MustRegister, it panics if registration fails for
+// any other reason.
+func WithMetrics(config *polymarket_gamma.ClientConfig, registerer prometheus.Registerer) *polymarket_gamma.ClientConfig {
+	var wired polymarket_gamma.ClientConfig
+	if config != nil {
+		wired = *config
+	}
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	collector := NewCollector(nil)
+	if err := registerer.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &registered) {
+			panic(err)
+		}
+		existing, ok := registered.ExistingCollector.(*Collector)
+		if !ok {
+			panic(err)
+		}
+		collector = existing
+	}
+
+	wired.Observer = polymarket_gamma.MultiObserver(wired.Observer, collector)
+	return &wired
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+	c.bytesReceived.Describe(ch)
+	c.bytesDecoded.Describe(ch)
+	c.keysetPages.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+	c.bytesReceived.Collect(ch)
+	c.bytesDecoded.Collect(ch)
+	c.keysetPages.Collect(ch)
+}
+
+// ObserveRequest updates the metrics for a completed request
+func (c *Collector) ObserveRequest(ctx context.Context, info *polymarket_gamma.RequestInfo) {
+	statusCode := ""
+	if info.StatusCode != 0 {
+		statusCode = strconv.Itoa(info.StatusCode)
+	}
+
+	c.requests.WithLabelValues(info.Endpoint, statusCode).Inc()
+	c.duration.WithLabelValues(info.Endpoint).Observe(info.Duration.Seconds())
+	c.bytesReceived.WithLabelValues(info.Endpoint).Add(float64(info.BytesReceived))
+	c.bytesDecoded.WithLabelValues(info.Endpoint).Add(float64(info.BytesDecoded))
+
+	if info.Err != nil {
+		c.errors.WithLabelValues(info.Endpoint, string(info.ErrorClass), statusCode).Inc()
+		return
+	}
+
+	if info.Endpoint == "/events/keyset" {
+		c.keysetPages.WithLabelValues(info.Endpoint).Inc()
+	}
+}
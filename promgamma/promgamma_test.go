@@ -0,0 +1,130 @@
+package promgamma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/events/keyset":
+			json.NewEncoder(w).Encode(polymarket_gamma.GetEventsKeysetResponse{
+				Events: []polymarket_gamma.Event{{ID: "1"}},
+			})
+		case "/events":
+			if r.URL.Query().Get("id") == "404" {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`not json`))
+		}
+	}))
+	defer server.Close()
+
+	collector := NewCollector(nil)
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL:  server.URL,
+		Observer: collector,
+	})
+
+	_, err := client.GetEventsByKeysetPage("", 100)
+	require.NoError(t, err)
+	_, err = client.GetEventsByKeysetPage("", 100)
+	require.NoError(t, err)
+	_, err = client.GetEventsByIDs([]int{404})
+	require.Error(t, err)
+	_, err = client.GetEventsByIDs([]int{1})
+	require.Error(t, err)
+
+	expected := `
+# HELP gamma_client_errors_total Failed Gamma API requests by error class (transport, http, parse, validation).
+# TYPE gamma_client_errors_total counter
+gamma_client_errors_total{class="http",endpoint="/events",status_code="404"} 1
+gamma_client_errors_total{class="parse",endpoint="/events",status_code="200"} 1
+# HELP gamma_client_keyset_pages_total Keyset pages successfully fetched from /events/keyset.
+# TYPE gamma_client_keyset_pages_total counter
+gamma_client_keyset_pages_total{endpoint="/events/keyset"} 2
+# HELP gamma_client_requests_total Requests made to the Gamma API.
+# TYPE gamma_client_requests_total counter
+gamma_client_requests_total{endpoint="/events",status_code="200"} 1
+gamma_client_requests_total{endpoint="/events",status_code="404"} 1
+gamma_client_requests_total{endpoint="/events/keyset",status_code="200"} 2
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"gamma_client_errors_total",
+		"gamma_client_keyset_pages_total",
+		"gamma_client_requests_total",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "gamma_client_request_duration_seconds"))
+	assert.Positive(t, testutil.ToFloat64(collector.bytesReceived.WithLabelValues("/events/keyset")))
+	assert.Equal(t,
+		testutil.ToFloat64(collector.bytesReceived.WithLabelValues("/events/keyset")),
+		testutil.ToFloat64(collector.bytesDecoded.WithLabelValues("/events/keyset")),
+	)
+}
+
+func TestCollectorNamespace(t *testing.T) {
+	collector := NewCollector(&Config{
+		Namespace:   "polymarket",
+		ConstLabels: prometheus.Labels{"service": "test"},
+	})
+	collector.ObserveRequest(t.Context(), &polymarket_gamma.RequestInfo{
+		Endpoint:   "/events",
+		StatusCode: http.StatusOK,
+	})
+
+	expected := `
+# HELP polymarket_client_requests_total Requests made to the Gamma API.
+# TYPE polymarket_client_requests_total counter
+polymarket_client_requests_total{endpoint="/events",service="test",status_code="200"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "polymarket_client_requests_total"))
+}
+
+func TestWithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var observed int
+	registry := prometheus.NewPedanticRegistry()
+	config := &polymarket_gamma.ClientConfig{
+		BaseURL: server.URL,
+		Observer: polymarket_gamma.ObserverFunc(func(ctx context.Context, info *polymarket_gamma.RequestInfo) {
+			observed++
+		}),
+	}
+
+	first := polymarket_gamma.NewClient(WithMetrics(config, registry))
+	second := polymarket_gamma.NewClient(WithMetrics(&polymarket_gamma.ClientConfig{BaseURL: server.URL}, registry))
+	_, err := first.GetEventsByPage(0, 10, true)
+	require.NoError(t, err)
+	_, err = second.GetEventsByPage(0, 10, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, observed, "the config's own observer is kept")
+	expected := `
+# HELP gamma_client_requests_total Requests made to the Gamma API.
+# TYPE gamma_client_requests_total counter
+gamma_client_requests_total{endpoint="/events",status_code="200"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "gamma_client_requests_total"),
+		"both clients share the registered collector")
+}
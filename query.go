@@ -0,0 +1,199 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// EventQuery selects a page of events from /events. Zero fields are left out of the
+// request, so the API's defaults apply; nil booleans match either value.
+type EventQuery struct {
+	Offset int
+	// Limit is the page size (the API defaults to 20 and caps it at 100)
+	Limit int
+	// Order is the sort field, such as id, volume, volume24hr, liquidity, startDate,
+	// endDate, createdAt or updatedAt
+	Order     string
+	Ascending bool
+
+	IDs     []int
+	Slugs   []string
+	TagID   int
+	TagSlug string
+	// RelatedTags also matches events carrying tags related to TagID
+	RelatedTags bool
+	SeriesID    int
+
+	Active   *bool
+	Closed   *bool
+	Archived *bool
+	Featured *bool
+
+	LiquidityMin float64
+	LiquidityMax float64
+	VolumeMin    float64
+	VolumeMax    float64
+	StartDateMin time.Time
+	StartDateMax time.Time
+	EndDateMin   time.Time
+	EndDateMax   time.Time
+}
+
+func (q *EventQuery) values() url.Values {
+	queryParams := url.Values{}
+	setPage(queryParams, q.Offset, q.Limit, q.Order, q.Ascending)
+
+	for _, id := range q.IDs {
+		queryParams.Add("id", strconv.Itoa(id))
+	}
+	for _, slug := range q.Slugs {
+		queryParams.Add("slug", slug)
+	}
+	setInt(queryParams, "tag_id", q.TagID)
+	setString(queryParams, "tag_slug", q.TagSlug)
+	if q.RelatedTags {
+		queryParams.Set("related_tags", "true")
+	}
+	setInt(queryParams, "series_id", q.SeriesID)
+
+	setBool(queryParams, "active", q.Active)
+	setBool(queryParams, "closed", q.Closed)
+	setBool(queryParams, "archived", q.Archived)
+	setBool(queryParams, "featured", q.Featured)
+
+	setFloat(queryParams, "liquidity_min", q.LiquidityMin)
+	setFloat(queryParams, "liquidity_max", q.LiquidityMax)
+	setFloat(queryParams, "volume_min", q.VolumeMin)
+	setFloat(queryParams, "volume_max", q.VolumeMax)
+	setTime(queryParams, "start_date_min", q.StartDateMin)
+	setTime(queryParams, "start_date_max", q.StartDateMax)
+	setTime(queryParams, "end_date_min", q.EndDateMin)
+	setTime(queryParams, "end_date_max", q.EndDateMax)
+
+	return queryParams
+}
+
+// MarketQuery selects a page of markets from /markets. Zero fields are left out of
+// the request, so the API's defaults apply; nil booleans match either value.
+type MarketQuery struct {
+	Offset int
+	// Limit is the page size (the API defaults to 20 and caps it at 100)
+	Limit int
+	// Order is the sort field, such as id, volume, volume24hr, liquidity, endDate or
+	// updatedAt
+	Order     string
+	Ascending bool
+
+	IDs          []int
+	Slugs        []string
+	ConditionIDs []string
+	ClobTokenIDs []string
+	TagID        int
+	// RelatedTags also matches markets carrying tags related to TagID
+	RelatedTags bool
+
+	Active   *bool
+	Closed   *bool
+	Archived *bool
+
+	LiquidityMin float64
+	LiquidityMax float64
+	VolumeMin    float64
+	VolumeMax    float64
+	StartDateMin time.Time
+	StartDateMax time.Time
+	EndDateMin   time.Time
+	EndDateMax   time.Time
+}
+
+func (q *MarketQuery) values() url.Values {
+	queryParams := url.Values{}
+	setPage(queryParams, q.Offset, q.Limit, q.Order, q.Ascending)
+
+	for _, id := range q.IDs {
+		queryParams.Add("id", strconv.Itoa(id))
+	}
+	for _, slug := range q.Slugs {
+		queryParams.Add("slug", slug)
+	}
+	for _, conditionID := range q.ConditionIDs {
+		queryParams.Add("condition_ids", conditionID)
+	}
+	for _, tokenID := range q.ClobTokenIDs {
+		queryParams.Add("clob_token_ids", tokenID)
+	}
+	setInt(queryParams, "tag_id", q.TagID)
+	if q.RelatedTags {
+		queryParams.Set("related_tags", "true")
+	}
+
+	setBool(queryParams, "active", q.Active)
+	setBool(queryParams, "closed", q.Closed)
+	setBool(queryParams, "archived", q.Archived)
+
+	setFloat(queryParams, "liquidity_num_min", q.LiquidityMin)
+	setFloat(queryParams, "liquidity_num_max", q.LiquidityMax)
+	setFloat(queryParams, "volume_num_min", q.VolumeMin)
+	setFloat(queryParams, "volume_num_max", q.VolumeMax)
+	setTime(queryParams, "start_date_min", q.StartDateMin)
+	setTime(queryParams, "start_date_max", q.StartDateMax)
+	setTime(queryParams, "end_date_min", q.EndDateMin)
+	setTime(queryParams, "end_date_max", q.EndDateMax)
+
+	return queryParams
+}
+
+// QueryEvents fetches the page of events selected by query. It is offset paginated,
+// so the offset cap applies.
+func (c *Client) QueryEvents(query *EventQuery) (*GetEventsResponse, error) {
+	return c.QueryEventsContext(context.Background(), query)
+}
+
+// QueryEventsContext is QueryEvents with a caller-supplied context
+func (c *Client) QueryEventsContext(ctx context.Context, query *EventQuery) (*GetEventsResponse, error) {
+	if query == nil {
+		query = &EventQuery{}
+	}
+	return c.getEvents(ctx, query.values())
+}
+
+func setPage(queryParams url.Values, offset, limit int, order string, ascending bool) {
+	setInt(queryParams, "offset", offset)
+	setInt(queryParams, "limit", limit)
+	setString(queryParams, "order", order)
+	if order != "" || ascending {
+		queryParams.Set("ascending", strconv.FormatBool(ascending))
+	}
+}
+
+func setString(queryParams url.Values, key, value string) {
+	if value != "" {
+		queryParams.Set(key, value)
+	}
+}
+
+func setInt(queryParams url.Values, key string, value int) {
+	if value != 0 {
+		queryParams.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setFloat(queryParams url.Values, key string, value float64) {
+	if value != 0 {
+		queryParams.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+}
+
+func setBool(queryParams url.Values, key string, value *bool) {
+	if value != nil {
+		queryParams.Set(key, strconv.FormatBool(*value))
+	}
+}
+
+func setTime(queryParams url.Values, key string, value time.Time) {
+	if !value.IsZero() {
+		queryParams.Set(key, value.UTC().Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,305 @@
+// Package recorder provides a record/replay http.RoundTripper for deterministic tests.
+//
+// In record mode real Gamma traffic is passed through and saved to a cassette file;
+// in replay mode responses are served from the cassette without touching the
+// network. Requests are matched on method, path and sorted query parameters.
+//
+//	transport, err := recorder.New(&recorder.Config{
+//	    Path: "testdata/cassettes/events.json",
+//	    Mode: recorder.ModeReplay,
+//	})
+//	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+//	    Transport: transport,
+//	})
+package recorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+type Mode string
+
+const (
+	// ModeRecord sends requests to the network and saves every interaction
+	ModeRecord Mode = "record"
+	// ModeReplay serves responses from the cassette and never touches the network
+	ModeReplay Mode = "replay"
+	// ModePassthrough sends requests to the network without recording
+	ModePassthrough Mode = "passthrough"
+)
+
+// ErrNoInteraction is returned in replay mode for requests missing from the cassette
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// ErrMissingCassette is returned by New in replay mode when the cassette file does
+// not exist
+var ErrMissingCassette = errors.New("missing cassette")
+
+type Config struct {
+	// Path of the cassette file
+	Path string
+	Mode Mode
+	// Transport used to reach the network in record and passthrough modes
+	// (default http.DefaultTransport)
+	Transport http.RoundTripper
+}
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Method string `json:"method"`
+	// Key is the method, path and sorted query used for matching
+	Key      string      `json:"key"`
+	URL      string      `json:"url"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     string      `json:"body"`
+	Encoding string      `json:"encoding,omitempty"`
+}
+
+// Cassette is the on-disk format: interactions in the order they were recorded
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Transport records or replays HTTP interactions
+type Transport struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	// replayed counts how many interactions have been served per key
+	replayed map[string]int
+}
+
+// New loads the cassette at config.Path (required in replay mode) and returns a
+// Transport for the given mode
+func New(config *Config) (*Transport, error) {
+	t := &Transport{
+		path:      config.Path,
+		mode:      config.Mode,
+		transport: config.Transport,
+		replayed:  map[string]int{},
+	}
+	if t.transport == nil {
+		t.transport = http.DefaultTransport
+	}
+
+	switch t.mode {
+	case ModeReplay:
+		if err := t.load(); err != nil {
+			return nil, err
+		}
+	case ModeRecord, ModePassthrough:
+	default:
+		return nil, fmt.Errorf("unknown recorder mode %q", t.mode)
+	}
+
+	return t, nil
+}
+
+// ModeFromEnv reads a Mode from the environment variable key, defaulting to
+// ModeReplay so tests never reach the network unless asked to
+func ModeFromEnv(key string) Mode {
+	if value := os.Getenv(key); value != "" {
+		return Mode(value)
+	}
+	return ModeReplay
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch t.mode {
+	case ModeReplay:
+		return t.replay(req)
+	case ModeRecord:
+		return t.record(req)
+	default:
+		return t.transport.RoundTrip(req)
+	}
+}
+
+// Interactions returns a copy of the interactions recorded or loaded so far
+func (t *Transport) Interactions() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Interaction(nil), t.cassette.Interactions...)
+}
+
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	key := matchKey(req)
+
+	t.mu.Lock()
+	var matches []Interaction
+	for _, interaction := range t.cassette.Interactions {
+		if interaction.Key == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%w for %s", ErrNoInteraction, key)
+	}
+
+	// Serve repeated requests in recorded order, then keep repeating the last one
+	i := t.replayed[key]
+	if i >= len(matches) {
+		i = len(matches) - 1
+	}
+	t.replayed[key]++
+	interaction := matches[i]
+	t.mu.Unlock()
+
+	body, err := interaction.body()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for recording: %w", err)
+	}
+
+	// Store bodies decompressed so cassettes stay readable and diffable
+	header := resp.Header.Clone()
+	stored := body
+	if strings.Contains(header.Get("Content-Encoding"), "gzip") {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader for recording: %w", err)
+		}
+		stored, err = io.ReadAll(gzipReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress response for recording: %w", err)
+		}
+		header.Del("Content-Encoding")
+		header.Del("Content-Length")
+	}
+
+	interaction := Interaction{
+		Method: req.Method,
+		Key:    matchKey(req),
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: header,
+	}
+	interaction.setBody(stored)
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	err = t.save()
+	t.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (t *Transport) load() error {
+	data, err := os.ReadFile(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w %s", ErrMissingCassette, t.path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.cassette); err != nil {
+		return fmt.Errorf("failed to parse cassette %s: %w", t.path, err)
+	}
+	return nil
+}
+
+// save writes the cassette atomically; the caller must hold t.mu
+func (t *Transport) save() error {
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// matchKey identifies a request by method, path and query with both keys and
+// values sorted, so parameter order does not affect matching
+func matchKey(req *http.Request) string {
+	query := url.Values{}
+	for key, values := range req.URL.Query() {
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		query[key] = sorted
+	}
+
+	key := req.Method + " " + req.URL.Path
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+	return key
+}
+
+// setBody stores body as text when it is valid UTF-8 and as base64 otherwise
+func (i *Interaction) setBody(body []byte) {
+	if utf8.Valid(body) {
+		i.Body = string(body)
+		return
+	}
+	i.Encoding = "base64"
+	i.Body = base64.StdEncoding.EncodeToString(body)
+}
+
+func (i *Interaction) body() ([]byte, error) {
+	if i.Encoding == "base64" {
+		body, err := base64.StdEncoding.DecodeString(i.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode recorded body for %s: %w", i.Key, err)
+		}
+		return body, nil
+	}
+	return []byte(i.Body), nil
+}
@@ -0,0 +1,149 @@
+package recorder
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGammaServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+
+		switch r.URL.Path {
+		case "/events":
+			gz.Write([]byte(`[{"id": "1", "title": "Recorded"}, {"id": "2", "title": "Recorded"}]`))
+		case "/events/keyset":
+			gz.Write([]byte(`{"events": [{"id": "` + r.URL.Query().Get("limit") + `"}], "next_cursor": ""}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	var hits atomic.Int32
+	server := newGammaServer(t, &hits)
+	path := filepath.Join(t.TempDir(), "cassettes", "events.json")
+
+	recording, err := New(&Config{Path: path, Mode: ModeRecord})
+	require.NoError(t, err)
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL:   server.URL,
+		Transport: recording,
+	})
+	recorded, err := client.GetEventsByIDs([]int{1, 2})
+	require.NoError(t, err)
+	_, err = client.GetEventsByKeysetPage("", 7)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), hits.Load())
+
+	interactions := recording.Interactions()
+	require.Len(t, interactions, 2)
+	assert.Equal(t, "GET /events?id=1&id=2&limit=2", interactions[0].Key)
+	assert.Empty(t, interactions[0].Header.Get("Content-Encoding"))
+	assert.Contains(t, interactions[0].Body, `"Recorded"`)
+
+	replaying, err := New(&Config{Path: path, Mode: ModeReplay})
+	require.NoError(t, err)
+
+	client = polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{
+		BaseURL:   server.URL,
+		Transport: replaying,
+	})
+
+	// Query parameter order does not matter when matching
+	replayed, err := client.GetEventsByIDs([]int{2, 1})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, recorded.Events, replayed.Events)
+
+	page, err := client.GetEventsByKeysetPage("", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "7", page.Events[0].ID)
+
+	assert.Equal(t, int32(2), hits.Load(), "replay must not touch the network")
+
+	_, err = client.GetEventsByIDs([]int{3})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func TestReplayServesRepeatedRequestsInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	responses := []string{`[{"id": "1", "title": "first"}]`, `[{"id": "1", "title": "second"}]`}
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responses[hits.Add(1)-1]))
+	}))
+	defer server.Close()
+
+	recording, err := New(&Config{Path: path, Mode: ModeRecord})
+	require.NoError(t, err)
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{BaseURL: server.URL, Transport: recording})
+	for range responses {
+		_, err := client.GetEventsByIDs([]int{1})
+		require.NoError(t, err)
+	}
+
+	replaying, err := New(&Config{Path: path, Mode: ModeReplay})
+	require.NoError(t, err)
+	client = polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{BaseURL: server.URL, Transport: replaying})
+
+	var titles []string
+	for i := 0; i < 3; i++ {
+		response, err := client.GetEventsByIDs([]int{1})
+		require.NoError(t, err)
+		titles = append(titles, response.Events[0].Title)
+	}
+	assert.Equal(t, []string{"first", "second", "second"}, titles)
+}
+
+func TestPassthroughDoesNotRecord(t *testing.T) {
+	var hits atomic.Int32
+	server := newGammaServer(t, &hits)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	transport, err := New(&Config{Path: path, Mode: ModePassthrough})
+	require.NoError(t, err)
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{BaseURL: server.URL, Transport: transport})
+	_, err = client.GetEventsByIDs([]int{1})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), hits.Load())
+	assert.Empty(t, transport.Interactions())
+	assert.NoFileExists(t, path)
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := New(&Config{Path: filepath.Join(t.TempDir(), "missing.json"), Mode: ModeReplay})
+	assert.ErrorIs(t, err, ErrMissingCassette)
+
+	dir := t.TempDir()
+	_, err = New(&Config{Path: dir, Mode: ModeReplay})
+	assert.ErrorContains(t, err, "failed to read cassette")
+
+	_, err = New(&Config{Mode: "rewind"})
+	assert.ErrorContains(t, err, `unknown recorder mode "rewind"`)
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv("GAMMA_CASSETTE", "")
+	assert.Equal(t, ModeReplay, ModeFromEnv("GAMMA_CASSETTE"))
+
+	t.Setenv("GAMMA_CASSETTE", "record")
+	assert.Equal(t, ModeRecord, ModeFromEnv("GAMMA_CASSETTE"))
+}
@@ -0,0 +1,35 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Search runs a full-text search for events and tags through the API's
+// /public-search endpoint. limitPerType bounds the results of each kind; zero uses
+// the API's default.
+func (c *Client) Search(query string, limitPerType int) (*SearchResponse, error) {
+	return c.SearchContext(context.Background(), query, limitPerType)
+}
+
+// SearchContext is Search with a caller-supplied context
+func (c *Client) SearchContext(ctx context.Context, query string, limitPerType int) (*SearchResponse, error) {
+	queryParams := url.Values{}
+	queryParams.Set("q", query)
+	if limitPerType > 0 {
+		queryParams.Set("limit_per_type", strconv.Itoa(limitPerType))
+	}
+
+	response, info, body, err := getJSON(ctx, c, "/public-search", queryParams, func(info *RequestInfo, response *SearchResponse) error {
+		info.Events = len(response.Events)
+		return c.validateEvents(info, response.Events)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.Meta = info.meta()
+	response.Raw = c.rawBody(body)
+	return response, nil
+}
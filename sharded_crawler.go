@@ -0,0 +1,210 @@
+package polymarket_gamma
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+const (
+	defaultShardConcurrency = 4
+	defaultShardBlockSize   = 100
+)
+
+type ShardedCrawlerConfig struct {
+	// Concurrency bounds how many ID blocks are fetched at once (default 4)
+	Concurrency int
+	// BlockSize is the number of IDs probed per block (default 100, one request)
+	BlockSize int
+	// MinID is the first ID probed (default 1)
+	MinID int
+	// MaxID is the last ID probed. When zero it is discovered from the newest event.
+	MaxID int
+	// Sink receives the events of each block in ascending ID order. Blocks are
+	// delivered in order, so the sink sees a single stream sorted by ID.
+	Sink func(ctx context.Context, events []Event) error
+	// Progress is called after each block is delivered (optional)
+	Progress func(ShardProgress)
+}
+
+// ShardProgress reports how far a sharded crawl has got
+type ShardProgress struct {
+	Blocks      int
+	TotalBlocks int
+	Events      int
+	// NextID is the first ID not yet delivered
+	NextID int
+}
+
+// ShardedCrawler enumerates events by splitting the ID space into blocks and probing
+// them concurrently with GetEventsByIDs. Unlike Crawler it is not bound to one
+// request per round trip, but it cannot resume and it only finds events up to MaxID.
+type ShardedCrawler struct {
+	api    EventsAPI
+	config ShardedCrawlerConfig
+}
+
+type shardResult struct {
+	events []Event
+	err    error
+}
+
+func NewShardedCrawler(api EventsAPI, config *ShardedCrawlerConfig) *ShardedCrawler {
+	if config == nil {
+		config = &ShardedCrawlerConfig{}
+	}
+
+	crawler := &ShardedCrawler{api: api, config: *config}
+	if crawler.config.Concurrency <= 0 {
+		crawler.config.Concurrency = defaultShardConcurrency
+	}
+	if crawler.config.BlockSize <= 0 {
+		crawler.config.BlockSize = defaultShardBlockSize
+	}
+	if crawler.config.MinID <= 0 {
+		crawler.config.MinID = 1
+	}
+	return crawler
+}
+
+// Run probes every block between MinID and MaxID, delivering them to the sink in ID
+// order. At most twice Concurrency blocks are buffered ahead of the sink.
+func (s *ShardedCrawler) Run(ctx context.Context) error {
+	if s.config.Sink == nil {
+		return errors.New("sharded crawler has no sink")
+	}
+
+	maxID := s.config.MaxID
+	if maxID == 0 {
+		var err error
+		if maxID, err = s.discoverMaxID(ctx); err != nil {
+			return err
+		}
+	}
+	if maxID < s.config.MinID {
+		return nil
+	}
+
+	blockSize := s.config.BlockSize
+	totalBlocks := (maxID - s.config.MinID + blockSize) / blockSize
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	results := make([]chan shardResult, totalBlocks)
+	for i := range results {
+		results[i] = make(chan shardResult, 1)
+	}
+
+	// window bounds how far fetching runs ahead of delivery
+	window := make(chan struct{}, 2*s.config.Concurrency)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for block := 0; block < totalBlocks; block++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- block:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < s.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for block := range jobs {
+				events, err := s.fetchBlock(ctx, s.blockIDs(block, maxID))
+				results[block] <- shardResult{events: events, err: err}
+			}
+		}()
+	}
+
+	progress := ShardProgress{TotalBlocks: totalBlocks, NextID: s.config.MinID}
+	for block := 0; block < totalBlocks; block++ {
+		var result shardResult
+		select {
+		case result = <-results[block]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		ids := s.blockIDs(block, maxID)
+		if result.err != nil {
+			return fmt.Errorf("failed to fetch IDs %d-%d: %w", ids[0], ids[len(ids)-1], result.err)
+		}
+		if err := s.config.Sink(ctx, result.events); err != nil {
+			return fmt.Errorf("failed to deliver IDs %d-%d: %w", ids[0], ids[len(ids)-1], err)
+		}
+		<-window
+
+		progress.Blocks++
+		progress.Events += len(result.events)
+		progress.NextID = ids[len(ids)-1] + 1
+		if s.config.Progress != nil {
+			s.config.Progress(progress)
+		}
+	}
+
+	return nil
+}
+
+// discoverMaxID returns the ID of the newest event
+func (s *ShardedCrawler) discoverMaxID(ctx context.Context) (int, error) {
+	response, err := s.api.GetEventsByPageContext(ctx, 0, 1, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to discover newest event: %w", err)
+	}
+	if len(response.Events) == 0 {
+		return 0, nil
+	}
+
+	maxID, err := strconv.Atoi(response.Events[0].ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to discover newest event: invalid ID %q", response.Events[0].ID)
+	}
+	return maxID, nil
+}
+
+func (s *ShardedCrawler) blockIDs(block, maxID int) []int {
+	first := s.config.MinID + block*s.config.BlockSize
+	last := min(first+s.config.BlockSize-1, maxID)
+
+	ids := make([]int, 0, last-first+1)
+	for id := first; id <= last; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// fetchBlock probes ids and returns the events found, sorted by ID
+func (s *ShardedCrawler) fetchBlock(ctx context.Context, ids []int) ([]Event, error) {
+	response, err := s.api.GetEventsByIDsContext(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	events := response.Events
+	slices.SortStableFunc(events, func(a, b Event) int {
+		idA, _ := strconv.Atoi(a.ID)
+		idB, _ := strconv.Atoi(b.ID)
+		return cmp.Compare(idA, idB)
+	})
+	return events, nil
+}
@@ -0,0 +1,133 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rangeAPI is an EventsAPI holding the events with the given IDs. It answers ID
+// lookups in reverse order and tracks the peak number of concurrent lookups.
+type rangeAPI struct {
+	EventsAPI
+
+	ids      map[int]bool
+	failID   int
+	inFlight atomic.Int32
+	peak     atomic.Int32
+
+	mu      sync.Mutex
+	lookups int
+}
+
+func newRangeAPI(ids ...int) *rangeAPI {
+	api := &rangeAPI{ids: map[int]bool{}}
+	for _, id := range ids {
+		api.ids[id] = true
+	}
+	return api
+}
+
+func (r *rangeAPI) GetEventsByIDsContext(ctx context.Context, ids []int) (*GetEventsResponse, error) {
+	current := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		previous := r.peak.Load()
+		if current <= previous || r.peak.CompareAndSwap(previous, current) {
+			break
+		}
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	r.mu.Lock()
+	r.lookups++
+	r.mu.Unlock()
+
+	response := &GetEventsResponse{}
+	for _, id := range slices.Backward(ids) {
+		if id == r.failID {
+			return nil, errors.New("bad gateway")
+		}
+		if r.ids[id] {
+			response.Events = append(response.Events, mockEvent(strconv.Itoa(id)))
+		}
+	}
+	return response, nil
+}
+
+func (r *rangeAPI) GetEventsByPageContext(ctx context.Context, offset, limit int, ascending bool) (*GetEventsResponse, error) {
+	newest := 0
+	for id := range r.ids {
+		newest = max(newest, id)
+	}
+	return &GetEventsResponse{Events: []Event{mockEvent(strconv.Itoa(newest))}}, nil
+}
+
+func TestShardedCrawlerDeliversInOrder(t *testing.T) {
+	var existing []int
+	for id := 1; id <= 95; id++ {
+		if id%7 != 0 {
+			existing = append(existing, id)
+		}
+	}
+	api := newRangeAPI(existing...)
+
+	var ids []string
+	var last ShardProgress
+	crawler := NewShardedCrawler(api, &ShardedCrawlerConfig{
+		Concurrency: 3,
+		BlockSize:   10,
+		Sink:        collectIDs(&ids),
+		Progress:    func(p ShardProgress) { last = p },
+	})
+	require.NoError(t, crawler.Run(context.Background()))
+
+	var want []string
+	for _, id := range existing {
+		want = append(want, strconv.Itoa(id))
+	}
+	assert.Equal(t, want, ids)
+	assert.Equal(t, 10, api.lookups, "MaxID is discovered from the newest event")
+	assert.LessOrEqual(t, api.peak.Load(), int32(3))
+	assert.Equal(t, ShardProgress{Blocks: 10, TotalBlocks: 10, Events: len(existing), NextID: 96}, last)
+}
+
+func TestShardedCrawlerRange(t *testing.T) {
+	api := newRangeAPI(1, 5, 10, 15, 20, 25)
+
+	var ids []string
+	crawler := NewShardedCrawler(api, &ShardedCrawlerConfig{
+		MinID:     5,
+		MaxID:     20,
+		BlockSize: 4,
+		Sink:      collectIDs(&ids),
+	})
+	require.NoError(t, crawler.Run(context.Background()))
+	assert.Equal(t, []string{"5", "10", "15", "20"}, ids)
+}
+
+func TestShardedCrawlerStopsOnError(t *testing.T) {
+	api := newRangeAPI(1, 2, 3)
+	api.failID = 25
+
+	var ids []string
+	crawler := NewShardedCrawler(api, &ShardedCrawlerConfig{
+		MaxID:     100,
+		BlockSize: 10,
+		Sink:      collectIDs(&ids),
+	})
+	err := crawler.Run(context.Background())
+	assert.ErrorContains(t, err, "failed to fetch IDs 21-30: bad gateway")
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+
+	err = NewShardedCrawler(api, nil).Run(context.Background())
+	assert.ErrorContains(t, err, "sharded crawler has no sink")
+}
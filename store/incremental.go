@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/bytedance/sonic"
+	bolt "go.etcd.io/bbolt"
+)
+
+// MetaUpdatedAtWatermark is the meta key holding the newest event updatedAt the
+// store is known to be current with, in RFC 3339 format
+const MetaUpdatedAtWatermark = "updated_at_watermark"
+
+const defaultWatermarkOverlap = time.Minute
+
+type IncrementalSyncConfig struct {
+	// PageSize is the number of events requested per page (default 100)
+	PageSize int
+	// Overlap re-fetches events updated this long before the watermark, to cover
+	// events whose updatedAt was committed out of order (default 1m)
+	Overlap time.Duration
+	// FullSync configures the full sync used when there is no watermark yet or the
+	// changes cannot be fetched incrementally (optional)
+	FullSync *SyncConfig
+}
+
+// IncrementalSyncResult describes what an incremental sync did
+type IncrementalSyncResult struct {
+	// Updated is the number of events upserted by the incremental pass
+	Updated int
+	// FullSync is set when the sync fell back to a full crawl, with the reason in
+	// FallbackReason
+	FullSync       bool
+	FallbackReason string
+	// Watermark is the stored watermark after the sync
+	Watermark time.Time
+}
+
+// IncrementalSync fetches the events updated since the stored watermark, newest
+// first, and upserts them. It falls back to a full Sync when there is no watermark,
+// when the changes reach past the offset cap, or when the API does not honour the
+// updatedAt ordering.
+//
+// Only Event.UpdatedAt is tracked; a market change that does not touch its event's
+// updatedAt is picked up by the next full sync.
+func (s *Store) IncrementalSync(ctx context.Context, api polymarket_gamma.EventsAPI, config *IncrementalSyncConfig) (*IncrementalSyncResult, error) {
+	if config == nil {
+		config = &IncrementalSyncConfig{}
+	}
+
+	pageSize := config.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	overlap := config.Overlap
+	if overlap == 0 {
+		overlap = defaultWatermarkOverlap
+	}
+
+	watermark, err := s.watermark()
+	if err != nil {
+		return nil, err
+	}
+	if watermark.IsZero() {
+		return s.fullSync(ctx, api, config, "no watermark")
+	}
+
+	since := watermark.Add(-overlap)
+	result := &IncrementalSyncResult{}
+	newest := watermark
+
+	for offset := 0; ; {
+		response, err := api.GetEventsByUpdatedAtPageContext(ctx, offset, pageSize, false)
+		var apiErr *polymarket_gamma.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+			return s.fullSync(ctx, api, config, "changes reach past the offset cap")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch changes at offset %d: %w", offset, err)
+		}
+
+		var changed []polymarket_gamma.Event
+		reachedWatermark := false
+		for i, event := range response.Events {
+			if i > 0 && event.UpdatedAt.After(response.Events[i-1].UpdatedAt) {
+				return s.fullSync(ctx, api, config, "events are not ordered by updatedAt")
+			}
+			if !event.UpdatedAt.After(since) {
+				reachedWatermark = true
+				break
+			}
+			changed = append(changed, event)
+			if event.UpdatedAt.After(newest) {
+				newest = event.UpdatedAt
+			}
+		}
+
+		if len(changed) > 0 {
+			if err := s.UpsertEvents(changed); err != nil {
+				return nil, err
+			}
+			result.Updated += len(changed)
+		}
+
+		if reachedWatermark || len(response.Events) < pageSize {
+			break
+		}
+		offset += len(response.Events)
+	}
+
+	if err := s.setWatermark(newest); err != nil {
+		return nil, err
+	}
+	result.Watermark = newest
+	return result, nil
+}
+
+func (s *Store) fullSync(ctx context.Context, api polymarket_gamma.EventsAPI, config *IncrementalSyncConfig, reason string) (*IncrementalSyncResult, error) {
+	if err := s.Sync(ctx, api, config.FullSync); err != nil {
+		return nil, err
+	}
+
+	newest, err := s.newestUpdatedAt()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setWatermark(newest); err != nil {
+		return nil, err
+	}
+
+	return &IncrementalSyncResult{FullSync: true, FallbackReason: reason, Watermark: newest}, nil
+}
+
+func (s *Store) watermark() (time.Time, error) {
+	value, err := s.Meta(MetaUpdatedAtWatermark)
+	if err != nil || value == nil {
+		return time.Time{}, err
+	}
+
+	watermark, err := time.Parse(time.RFC3339Nano, string(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse watermark: %w", err)
+	}
+	return watermark, nil
+}
+
+func (s *Store) setWatermark(watermark time.Time) error {
+	if watermark.IsZero() {
+		return nil
+	}
+	return s.SetMeta(MetaUpdatedAtWatermark, []byte(watermark.UTC().Format(time.RFC3339Nano)))
+}
+
+// newestUpdatedAt scans the stored events for the largest updatedAt
+func (s *Store) newestUpdatedAt() (time.Time, error) {
+	var newest time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketEvents).ForEach(func(_, data []byte) error {
+			var event struct {
+				UpdatedAt time.Time `json:"updatedAt"`
+			}
+			if err := sonic.Unmarshal(data, &event); err != nil {
+				return err
+			}
+			if event.UpdatedAt.After(newest) {
+				newest = event.UpdatedAt
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find watermark: %w", err)
+	}
+	return newest, nil
+}
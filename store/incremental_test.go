@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/gammatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+func updatedEvent(id string, updatedAt time.Duration, title string) polymarket_gamma.Event {
+	event := testEvent(id, "event-"+id)
+	event.Title = title
+	event.UpdatedAt = epoch.Add(updatedAt)
+	return event
+}
+
+func TestIncrementalSync(t *testing.T) {
+	server := gammatest.NewServer(&gammatest.Config{Events: []polymarket_gamma.Event{
+		updatedEvent("1", 1*time.Hour, "one"),
+		updatedEvent("2", 2*time.Hour, "two"),
+		updatedEvent("3", 3*time.Hour, "three"),
+	}})
+	defer server.Close()
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{BaseURL: server.URL})
+	s := openStore(t)
+	config := &IncrementalSyncConfig{PageSize: 2, Overlap: time.Second}
+
+	// The first sync has no watermark to start from
+	result, err := s.IncrementalSync(context.Background(), client, config)
+	require.NoError(t, err)
+	assert.True(t, result.FullSync)
+	assert.Equal(t, "no watermark", result.FallbackReason)
+	assert.Equal(t, epoch.Add(3*time.Hour), result.Watermark)
+
+	server.UpsertEvent(updatedEvent("1", 4*time.Hour, "one, edited"))
+	server.UpsertEvent(updatedEvent("4", 5*time.Hour, "four"))
+
+	result, err = s.IncrementalSync(context.Background(), client, config)
+	require.NoError(t, err)
+	assert.False(t, result.FullSync)
+	// Event 3 sits on the previous watermark, inside the overlap, so it is re-fetched
+	assert.Equal(t, 3, result.Updated)
+	assert.Equal(t, epoch.Add(5*time.Hour), result.Watermark)
+
+	edited, err := s.Event("1")
+	require.NoError(t, err)
+	assert.Equal(t, "one, edited", edited.Title)
+	_, err = s.Event("4")
+	require.NoError(t, err)
+
+	// Nothing changed since, so only the event on the watermark is re-fetched
+	result, err = s.IncrementalSync(context.Background(), client, config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, epoch.Add(5*time.Hour), result.Watermark)
+}
+
+func TestIncrementalSyncFallsBackPastOffsetCap(t *testing.T) {
+	server := gammatest.NewServer(&gammatest.Config{
+		Events:    []polymarket_gamma.Event{updatedEvent("1", time.Hour, "one")},
+		MaxOffset: 1,
+	})
+	defer server.Close()
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{BaseURL: server.URL})
+	s := openStore(t)
+	config := &IncrementalSyncConfig{PageSize: 1, Overlap: time.Second}
+
+	_, err := s.IncrementalSync(context.Background(), client, config)
+	require.NoError(t, err)
+
+	for i, id := range []string{"2", "3", "4"} {
+		server.UpsertEvent(updatedEvent(id, time.Duration(i+2)*time.Hour, id))
+	}
+
+	result, err := s.IncrementalSync(context.Background(), client, config)
+	require.NoError(t, err)
+	assert.True(t, result.FullSync)
+	assert.Equal(t, "changes reach past the offset cap", result.FallbackReason)
+
+	stats, err := s.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.Events)
+}
+
+func TestIncrementalSyncFallsBackWhenOrderingIsIgnored(t *testing.T) {
+	api := &gammatest.MockEventsAPI{
+		GetEventsByUpdatedAtPageFunc: func(ctx context.Context, offset, limit int, ascending bool) (*polymarket_gamma.GetEventsResponse, error) {
+			return &polymarket_gamma.GetEventsResponse{Events: []polymarket_gamma.Event{
+				updatedEvent("1", 3*time.Hour, "one"),
+				updatedEvent("2", 4*time.Hour, "two"),
+			}}, nil
+		},
+		GetEventsByKeysetPageFunc: func(ctx context.Context, afterCursor string, limit int) (*polymarket_gamma.GetEventsKeysetResponse, error) {
+			return &polymarket_gamma.GetEventsKeysetResponse{Events: []polymarket_gamma.Event{
+				updatedEvent("1", 3*time.Hour, "one"),
+				updatedEvent("2", 4*time.Hour, "two"),
+			}}, nil
+		},
+	}
+
+	s := openStore(t)
+	require.NoError(t, s.setWatermark(epoch))
+
+	result, err := s.IncrementalSync(context.Background(), api, nil)
+	require.NoError(t, err)
+	assert.True(t, result.FullSync)
+	assert.Equal(t, "events are not ordered by updatedAt", result.FallbackReason)
+	assert.Equal(t, epoch.Add(4*time.Hour), result.Watermark)
+}
@@ -0,0 +1,517 @@
+// Package store keeps a local snapshot of the Gamma catalogue in an embedded bbolt
+// database.
+//
+// Events, markets, tags and series are stored once each, keyed by ID, with join
+// buckets linking events to their markets, tags and series in API order and index
+// buckets for lookups by slug, condition ID and CLOB token ID. Upserting an event
+// replaces its links and index entries, so the snapshot converges on the API.
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/bytedance/sonic"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned when a record is not in the store
+var ErrNotFound = errors.New("not found")
+
+var (
+	bucketEvents  = []byte("events")
+	bucketMarkets = []byte("markets")
+	bucketTags    = []byte("tags")
+	bucketSeries  = []byte("series")
+
+	bucketEventMarkets = []byte("event_markets")
+	bucketEventTags    = []byte("event_tags")
+	bucketEventSeries  = []byte("event_series")
+
+	bucketEventSlugs       = []byte("idx_event_slug")
+	bucketMarketSlugs      = []byte("idx_market_slug")
+	bucketMarketConditions = []byte("idx_market_condition_id")
+	bucketMarketClobTokens = []byte("idx_market_clob_token_id")
+	bucketTagSlugs         = []byte("idx_tag_slug")
+	bucketSeriesSlugs      = []byte("idx_series_slug")
+	bucketMeta             = []byte("meta")
+	allBuckets             = [][]byte{
+		bucketEvents, bucketMarkets, bucketTags, bucketSeries,
+		bucketEventMarkets, bucketEventTags, bucketEventSeries,
+		bucketEventSlugs, bucketMarketSlugs, bucketMarketConditions, bucketMarketClobTokens,
+		bucketTagSlugs, bucketSeriesSlugs, bucketMeta,
+	}
+)
+
+type Config struct {
+	// Timeout bounds how long Open waits for the database file lock (default 1s)
+	Timeout time.Duration
+}
+
+// Store is a snapshot of the Gamma catalogue. It is safe for concurrent use.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the database at path
+func Open(path string, config *Config) (*Store, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = time.Second
+	}
+
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// UpsertEvents stores events, their markets, tags and series in one transaction,
+// replacing any previous version of each
+func (s *Store) UpsertEvents(events []polymarket_gamma.Event) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for i := range events {
+			if err := upsertEvent(tx, &events[i]); err != nil {
+				return fmt.Errorf("event %s: %w", events[i].ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert events: %w", err)
+	}
+	return nil
+}
+
+// DeleteEvent removes an event and its links. Its markets, tags and series are kept.
+func (s *Store) DeleteEvent(id string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		key := idKey(id)
+		if err := unlinkEvent(tx, key); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketEvents).Delete(key)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete event %s: %w", id, err)
+	}
+	return nil
+}
+
+// Event returns the event with the given ID, with its markets, tags and series
+func (s *Store) Event(id string) (*polymarket_gamma.Event, error) {
+	var event *polymarket_gamma.Event
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		event, err = readEvent(tx, idKey(id))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event %s: %w", id, err)
+	}
+	return event, nil
+}
+
+// EventBySlug returns the event with the given slug
+func (s *Store) EventBySlug(slug string) (*polymarket_gamma.Event, error) {
+	var event *polymarket_gamma.Event
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := tx.Bucket(bucketEventSlugs).Get([]byte(slug))
+		if key == nil {
+			return ErrNotFound
+		}
+		var err error
+		event, err = readEvent(tx, key)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event %q: %w", slug, err)
+	}
+	return event, nil
+}
+
+// ForEachEvent calls fn for every event in ascending ID order, stopping at the first
+// error fn returns
+func (s *Store) ForEachEvent(fn func(event *polymarket_gamma.Event) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketEvents).ForEach(func(key, _ []byte) error {
+			event, err := readEvent(tx, key)
+			if err != nil {
+				return fmt.Errorf("failed to read event: %w", err)
+			}
+			return fn(event)
+		})
+	})
+}
+
+// Market returns the market with the given ID
+func (s *Store) Market(id string) (*polymarket_gamma.Market, error) {
+	return s.market(nil, idKey(id), id)
+}
+
+// MarketBySlug returns the market with the given slug
+func (s *Store) MarketBySlug(slug string) (*polymarket_gamma.Market, error) {
+	return s.market(bucketMarketSlugs, []byte(slug), slug)
+}
+
+// MarketByConditionID returns the market with the given condition ID
+func (s *Store) MarketByConditionID(conditionID string) (*polymarket_gamma.Market, error) {
+	return s.market(bucketMarketConditions, []byte(conditionID), conditionID)
+}
+
+// MarketByClobTokenID returns the market trading the given CLOB token
+func (s *Store) MarketByClobTokenID(tokenID string) (*polymarket_gamma.Market, error) {
+	return s.market(bucketMarketClobTokens, []byte(tokenID), tokenID)
+}
+
+// Tag returns the tag with the given ID
+func (s *Store) Tag(id string) (*polymarket_gamma.Tag, error) {
+	var tag polymarket_gamma.Tag
+	if err := s.get(bucketTags, idKey(id), &tag); err != nil {
+		return nil, fmt.Errorf("failed to read tag %s: %w", id, err)
+	}
+	return &tag, nil
+}
+
+// Series returns the series with the given ID
+func (s *Store) Series(id string) (*polymarket_gamma.Series, error) {
+	var series polymarket_gamma.Series
+	if err := s.get(bucketSeries, idKey(id), &series); err != nil {
+		return nil, fmt.Errorf("failed to read series %s: %w", id, err)
+	}
+	return &series, nil
+}
+
+// Stats counts the records in the store
+type Stats struct {
+	Events  int
+	Markets int
+	Tags    int
+	Series  int
+}
+
+func (s *Store) Stats() (Stats, error) {
+	var stats Stats
+	err := s.db.View(func(tx *bolt.Tx) error {
+		stats.Events = tx.Bucket(bucketEvents).Stats().KeyN
+		stats.Markets = tx.Bucket(bucketMarkets).Stats().KeyN
+		stats.Tags = tx.Bucket(bucketTags).Stats().KeyN
+		stats.Series = tx.Bucket(bucketSeries).Stats().KeyN
+		return nil
+	})
+	return stats, err
+}
+
+// SetMeta stores a small piece of bookkeeping, such as a sync watermark
+func (s *Store) SetMeta(key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Put([]byte(key), value)
+	})
+}
+
+// Meta returns the value stored by SetMeta, or nil if there is none
+func (s *Store) Meta(key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value = bytes.Clone(tx.Bucket(bucketMeta).Get([]byte(key)))
+		return nil
+	})
+	return value, err
+}
+
+// market reads a market by key, or through index when it is not nil
+func (s *Store) market(index, key []byte, name string) (*polymarket_gamma.Market, error) {
+	var market polymarket_gamma.Market
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if index != nil {
+			key = tx.Bucket(index).Get(key)
+			if key == nil {
+				return ErrNotFound
+			}
+		}
+		return getJSON(tx.Bucket(bucketMarkets), key, &market)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read market %q: %w", name, err)
+	}
+	return &market, nil
+}
+
+func (s *Store) get(bucket, key []byte, v any) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(bucket), key, v)
+	})
+}
+
+func upsertEvent(tx *bolt.Tx, event *polymarket_gamma.Event) error {
+	key := idKey(event.ID)
+	if err := unlinkEvent(tx, key); err != nil {
+		return err
+	}
+
+	for i := range event.Markets {
+		marketKey, err := upsertMarket(tx, &event.Markets[i])
+		if err != nil {
+			return err
+		}
+		if err := link(tx.Bucket(bucketEventMarkets), key, i, marketKey); err != nil {
+			return err
+		}
+	}
+	for i := range event.Tags {
+		tagKey := idKey(event.Tags[i].ID)
+		if err := upsertIndexed(tx, bucketTags, bucketTagSlugs, tagKey, event.Tags[i].Slug, &event.Tags[i]); err != nil {
+			return err
+		}
+		if err := link(tx.Bucket(bucketEventTags), key, i, tagKey); err != nil {
+			return err
+		}
+	}
+	for i := range event.Series {
+		seriesKey := idKey(event.Series[i].ID)
+		if err := upsertIndexed(tx, bucketSeries, bucketSeriesSlugs, seriesKey, event.Series[i].Slug, &event.Series[i]); err != nil {
+			return err
+		}
+		if err := link(tx.Bucket(bucketEventSeries), key, i, seriesKey); err != nil {
+			return err
+		}
+	}
+
+	// The children live in their own buckets
+	stored := *event
+	stored.Markets, stored.Tags, stored.Series = nil, nil, nil
+
+	if event.Slug != "" {
+		if err := tx.Bucket(bucketEventSlugs).Put([]byte(event.Slug), key); err != nil {
+			return err
+		}
+	}
+	return putJSON(tx.Bucket(bucketEvents), key, &stored)
+}
+
+// unlinkEvent removes an event's join entries and slug index entry
+func unlinkEvent(tx *bolt.Tx, key []byte) error {
+	var previous polymarket_gamma.Event
+	err := getJSON(tx.Bucket(bucketEvents), key, &previous)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if previous.Slug != "" {
+		if err := deleteIndex(tx.Bucket(bucketEventSlugs), previous.Slug, key); err != nil {
+			return err
+		}
+	}
+	for _, name := range [][]byte{bucketEventMarkets, bucketEventTags, bucketEventSeries} {
+		if err := unlink(tx.Bucket(name), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertMarket stores market and refreshes its index entries
+func upsertMarket(tx *bolt.Tx, market *polymarket_gamma.Market) ([]byte, error) {
+	key := idKey(market.ID)
+	markets := tx.Bucket(bucketMarkets)
+
+	var previous polymarket_gamma.Market
+	err := getJSON(markets, key, &previous)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		if err := indexMarket(tx, &previous, key, deleteIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := indexMarket(tx, market, key, putIndex); err != nil {
+		return nil, err
+	}
+	return key, putJSON(markets, key, market)
+}
+
+func indexMarket(tx *bolt.Tx, market *polymarket_gamma.Market, key []byte, apply func(*bolt.Bucket, string, []byte) error) error {
+	if err := apply(tx.Bucket(bucketMarketSlugs), market.Slug, key); err != nil {
+		return err
+	}
+	if err := apply(tx.Bucket(bucketMarketConditions), market.ConditionID, key); err != nil {
+		return err
+	}
+	for _, tokenID := range clobTokenIDs(market) {
+		if err := apply(tx.Bucket(bucketMarketClobTokens), tokenID, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clobTokenIDs decodes the JSON array the API sends as a string
+func clobTokenIDs(market *polymarket_gamma.Market) []string {
+	var ids []string
+	if market.ClobTokenIds != "" {
+		json.Unmarshal([]byte(market.ClobTokenIds), &ids)
+	}
+	return ids
+}
+
+// upsertIndexed stores a record that is only indexed by slug
+func upsertIndexed(tx *bolt.Tx, bucket, slugs, key []byte, slug string, record any) error {
+	var previous struct {
+		Slug string `json:"slug"`
+	}
+	err := getJSON(tx.Bucket(bucket), key, &previous)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err := deleteIndex(tx.Bucket(slugs), previous.Slug, key); err != nil {
+		return err
+	}
+	if err := putIndex(tx.Bucket(slugs), slug, key); err != nil {
+		return err
+	}
+	return putJSON(tx.Bucket(bucket), key, record)
+}
+
+func putIndex(bucket *bolt.Bucket, value string, key []byte) error {
+	if value == "" {
+		return nil
+	}
+	return bucket.Put([]byte(value), key)
+}
+
+// deleteIndex removes an index entry if it still points at key
+func deleteIndex(bucket *bolt.Bucket, value string, key []byte) error {
+	if value == "" || !bytes.Equal(bucket.Get([]byte(value)), key) {
+		return nil
+	}
+	return bucket.Delete([]byte(value))
+}
+
+// link records child as the position'th child of parent. Join keys are the parent
+// key, a separator and the big-endian position, so a prefix scan returns children
+// in API order.
+func link(bucket *bolt.Bucket, parent []byte, position int, child []byte) error {
+	return bucket.Put(joinKey(parent, position), child)
+}
+
+func unlink(bucket *bolt.Bucket, parent []byte) error {
+	prefix := joinPrefix(parent)
+	var keys [][]byte
+	cursor := bucket.Cursor()
+	for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+		keys = append(keys, bytes.Clone(key))
+	}
+	for _, key := range keys {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func children(bucket *bolt.Bucket, parent []byte) [][]byte {
+	prefix := joinPrefix(parent)
+	var keys [][]byte
+	cursor := bucket.Cursor()
+	for key, child := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, child = cursor.Next() {
+		keys = append(keys, child)
+	}
+	return keys
+}
+
+func readEvent(tx *bolt.Tx, key []byte) (*polymarket_gamma.Event, error) {
+	var event polymarket_gamma.Event
+	if err := getJSON(tx.Bucket(bucketEvents), key, &event); err != nil {
+		return nil, err
+	}
+
+	for _, child := range children(tx.Bucket(bucketEventMarkets), key) {
+		var market polymarket_gamma.Market
+		if err := getJSON(tx.Bucket(bucketMarkets), child, &market); err != nil {
+			return nil, fmt.Errorf("market: %w", err)
+		}
+		event.Markets = append(event.Markets, market)
+	}
+	for _, child := range children(tx.Bucket(bucketEventTags), key) {
+		var tag polymarket_gamma.Tag
+		if err := getJSON(tx.Bucket(bucketTags), child, &tag); err != nil {
+			return nil, fmt.Errorf("tag: %w", err)
+		}
+		event.Tags = append(event.Tags, tag)
+	}
+	for _, child := range children(tx.Bucket(bucketEventSeries), key) {
+		var series polymarket_gamma.Series
+		if err := getJSON(tx.Bucket(bucketSeries), child, &series); err != nil {
+			return nil, fmt.Errorf("series: %w", err)
+		}
+		event.Series = append(event.Series, series)
+	}
+
+	return &event, nil
+}
+
+func getJSON(bucket *bolt.Bucket, key []byte, v any) error {
+	data := bucket.Get(key)
+	if data == nil {
+		return ErrNotFound
+	}
+	return sonic.Unmarshal(data, v)
+}
+
+func putJSON(bucket *bolt.Bucket, key []byte, v any) error {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
+
+// idKey encodes numeric IDs with a fixed width so keys sort numerically
+func idKey(id string) []byte {
+	if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return fmt.Appendf(nil, "%020d", n)
+	}
+	return []byte(id)
+}
+
+func joinPrefix(parent []byte) []byte {
+	return append(bytes.Clone(parent), 0)
+}
+
+func joinKey(parent []byte, position int) []byte {
+	return binary.BigEndian.AppendUint32(joinPrefix(parent), uint32(position))
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/CalderWhite/polymarket-gamma-go/gammatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "gamma.db"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func testEvent(id, slug string, markets ...polymarket_gamma.Market) polymarket_gamma.Event {
+	return polymarket_gamma.Event{
+		ID:      id,
+		Slug:    slug,
+		Title:   "Event " + id,
+		Markets: markets,
+		Tags:    []polymarket_gamma.Tag{{ID: "7", Slug: "politics", Label: "Politics"}},
+		Series:  []polymarket_gamma.Series{{ID: "3", Slug: "weekly", Title: "Weekly"}},
+	}
+}
+
+func testMarket(id, conditionID string, tokens string) polymarket_gamma.Market {
+	return polymarket_gamma.Market{
+		ID:           id,
+		Slug:         "market-" + id,
+		Question:     "Question " + id,
+		ConditionID:  conditionID,
+		ClobTokenIds: tokens,
+	}
+}
+
+func TestUpsertAndLookup(t *testing.T) {
+	s := openStore(t)
+
+	event := testEvent("10", "election",
+		testMarket("101", "0xaaa", `["111", "222"]`),
+		testMarket("102", "0xbbb", `["333"]`),
+	)
+	require.NoError(t, s.UpsertEvents([]polymarket_gamma.Event{event}))
+
+	stored, err := s.Event("10")
+	require.NoError(t, err)
+	assert.Equal(t, event, *stored)
+
+	bySlug, err := s.EventBySlug("election")
+	require.NoError(t, err)
+	assert.Equal(t, "10", bySlug.ID)
+
+	market, err := s.MarketByConditionID("0xbbb")
+	require.NoError(t, err)
+	assert.Equal(t, "102", market.ID)
+
+	market, err = s.MarketByClobTokenID("222")
+	require.NoError(t, err)
+	assert.Equal(t, "101", market.ID)
+
+	market, err = s.MarketBySlug("market-102")
+	require.NoError(t, err)
+	assert.Equal(t, "102", market.ID)
+
+	tag, err := s.Tag("7")
+	require.NoError(t, err)
+	assert.Equal(t, "Politics", tag.Label)
+
+	series, err := s.Series("3")
+	require.NoError(t, err)
+	assert.Equal(t, "Weekly", series.Title)
+
+	_, err = s.Event("11")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpsertReplacesLinksAndIndexes(t *testing.T) {
+	s := openStore(t)
+
+	require.NoError(t, s.UpsertEvents([]polymarket_gamma.Event{
+		testEvent("10", "old-slug", testMarket("101", "0xaaa", `["111"]`), testMarket("102", "0xbbb", "")),
+	}))
+
+	updated := testEvent("10", "new-slug", testMarket("101", "0xccc", `["444"]`))
+	updated.Tags = nil
+	require.NoError(t, s.UpsertEvents([]polymarket_gamma.Event{updated}))
+
+	stored, err := s.Event("10")
+	require.NoError(t, err)
+	require.Len(t, stored.Markets, 1)
+	assert.Equal(t, "0xccc", stored.Markets[0].ConditionID)
+	assert.Empty(t, stored.Tags)
+
+	_, err = s.EventBySlug("old-slug")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.MarketByConditionID("0xaaa")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.MarketByClobTokenID("111")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	market, err := s.MarketByClobTokenID("444")
+	require.NoError(t, err)
+	assert.Equal(t, "101", market.ID)
+
+	require.NoError(t, s.DeleteEvent("10"))
+	_, err = s.Event("10")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.EventBySlug("new-slug")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestForEachEventIsOrderedByID(t *testing.T) {
+	s := openStore(t)
+	require.NoError(t, s.UpsertEvents([]polymarket_gamma.Event{
+		testEvent("100", "c"), testEvent("9", "a"), testEvent("20", "b"),
+	}))
+
+	var ids []string
+	require.NoError(t, s.ForEachEvent(func(event *polymarket_gamma.Event) error {
+		ids = append(ids, event.ID)
+		return nil
+	}))
+	assert.Equal(t, []string{"9", "20", "100"}, ids)
+}
+
+func TestSync(t *testing.T) {
+	var events []polymarket_gamma.Event
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		events = append(events, testEvent(id, "event-"+id, testMarket("m"+id, "0x"+id, "")))
+	}
+	server := gammatest.NewServer(&gammatest.Config{Events: events})
+	defer server.Close()
+
+	client := polymarket_gamma.NewClient(&polymarket_gamma.ClientConfig{BaseURL: server.URL})
+	s := openStore(t)
+
+	pages := 0
+	require.NoError(t, s.Sync(context.Background(), client, &SyncConfig{
+		PageSize: 2,
+		Progress: func(polymarket_gamma.CrawlCheckpoint) { pages++ },
+	}))
+	assert.Equal(t, 3, pages)
+
+	stats, err := s.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Events: 5, Markets: 5, Tags: 1, Series: 1}, stats)
+
+	synced, err := s.Meta(MetaLastFullSync)
+	require.NoError(t, err)
+	assert.NotEmpty(t, synced)
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+// MetaLastFullSync is the meta key holding the RFC 3339 time the last full sync
+// completed
+const MetaLastFullSync = "last_full_sync"
+
+type SyncConfig struct {
+	// CheckpointPath lets an interrupted sync resume (optional)
+	CheckpointPath string
+	// ActiveOnly syncs only active events
+	ActiveOnly bool
+	// PageSize is the number of events requested per page (default 100)
+	PageSize int
+	// Progress is called after every page is stored (optional)
+	Progress func(polymarket_gamma.CrawlCheckpoint)
+}
+
+// Sync walks the whole catalogue with keyset pagination and upserts every page into
+// the store. Each page is committed before the crawl checkpoint advances, so a
+// resumed sync never skips a page.
+func (s *Store) Sync(ctx context.Context, api polymarket_gamma.EventsAPI, config *SyncConfig) error {
+	if config == nil {
+		config = &SyncConfig{}
+	}
+
+	crawler := polymarket_gamma.NewCrawler(api, &polymarket_gamma.CrawlerConfig{
+		CheckpointPath: config.CheckpointPath,
+		ActiveOnly:     config.ActiveOnly,
+		PageSize:       config.PageSize,
+		Progress:       config.Progress,
+		Sink: func(ctx context.Context, events []polymarket_gamma.Event) error {
+			return s.UpsertEvents(events)
+		},
+	})
+	if err := crawler.Run(ctx); err != nil {
+		return fmt.Errorf("failed to sync: %w", err)
+	}
+
+	return s.SetMeta(MetaLastFullSync, []byte(time.Now().UTC().Format(time.RFC3339)))
+}
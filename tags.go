@@ -0,0 +1,53 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// GetTagsByPage fetches a page of tags from the Polymarket Gamma API
+func (c *Client) GetTagsByPage(offset, limit int) (*GetTagsResponse, error) {
+	return c.GetTagsByPageContext(context.Background(), offset, limit)
+}
+
+// GetTagsByPageContext is GetTagsByPage with a caller-supplied context
+func (c *Client) GetTagsByPageContext(ctx context.Context, offset, limit int) (*GetTagsResponse, error) {
+	queryParams := url.Values{}
+	queryParams.Set("offset", strconv.Itoa(offset))
+	queryParams.Set("limit", strconv.Itoa(limit))
+
+	tags, info, body, err := getJSON[[]Tag](ctx, c, "/tags", queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetTagsResponse{
+		Tags: *tags,
+		Meta: info.meta(),
+		Raw:  c.rawBody(body),
+	}, nil
+}
+
+// GetSeriesByPage fetches a page of series from the Polymarket Gamma API
+func (c *Client) GetSeriesByPage(offset, limit int) (*GetSeriesResponse, error) {
+	return c.GetSeriesByPageContext(context.Background(), offset, limit)
+}
+
+// GetSeriesByPageContext is GetSeriesByPage with a caller-supplied context
+func (c *Client) GetSeriesByPageContext(ctx context.Context, offset, limit int) (*GetSeriesResponse, error) {
+	queryParams := url.Values{}
+	queryParams.Set("offset", strconv.Itoa(offset))
+	queryParams.Set("limit", strconv.Itoa(limit))
+
+	series, info, body, err := getJSON[[]Series](ctx, c, "/series", queryParams, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetSeriesResponse{
+		Series: *series,
+		Meta:   info.meta(),
+		Raw:    c.rawBody(body),
+	}, nil
+}
@@ -1,6 +1,9 @@
 package polymarket_gamma
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -209,6 +212,64 @@ type Market struct {
 // GetEventsResponse represents the response from the events endpoint
 type GetEventsResponse struct {
 	Events []Event `json:"events"`
+	// MissingIDs lists the requested IDs that the API did not return (set by
+	// GetEventsByIDs only)
+	MissingIDs []int `json:"-"`
+	// Meta describes the HTTP exchange that produced this response. It is nil when
+	// GetEventsByIDs had to split the IDs over several requests.
+	Meta *ResponseMeta `json:"-"`
+	// Raw is the decompressed response body, set when ClientConfig.RetainRawBody is
+	// true (and, like Meta, only when a single request was made)
+	Raw []byte `json:"-"`
+}
+
+// GetMarketsResponse represents the response from the markets endpoint
+type GetMarketsResponse struct {
+	Markets []Market `json:"markets"`
+	// MissingIDs lists the requested IDs that the API did not return (set by
+	// GetMarketsByIDs only)
+	MissingIDs []int `json:"-"`
+	// Meta describes the HTTP exchange that produced this response. It is nil when
+	// GetMarketsByIDs had to split the IDs over several requests.
+	Meta *ResponseMeta `json:"-"`
+	// Raw is the decompressed response body, set when ClientConfig.RetainRawBody is
+	// true (and, like Meta, only when a single request was made)
+	Raw []byte `json:"-"`
+}
+
+// GetTagsResponse represents the response from the tags endpoint
+type GetTagsResponse struct {
+	Tags []Tag `json:"tags"`
+	// Meta describes the HTTP exchange that produced this response
+	Meta *ResponseMeta `json:"-"`
+	// Raw is the decompressed response body, set when ClientConfig.RetainRawBody is true
+	Raw []byte `json:"-"`
+}
+
+// GetSeriesResponse represents the response from the series endpoint
+type GetSeriesResponse struct {
+	Series []Series `json:"series"`
+	// Meta describes the HTTP exchange that produced this response
+	Meta *ResponseMeta `json:"-"`
+	// Raw is the decompressed response body, set when ClientConfig.RetainRawBody is true
+	Raw []byte `json:"-"`
+}
+
+// SearchResponse represents the response from the public search endpoint
+type SearchResponse struct {
+	Events     []Event          `json:"events"`
+	Tags       []Tag            `json:"tags"`
+	Pagination SearchPagination `json:"pagination"`
+	// Meta describes the HTTP exchange that produced this response
+	Meta *ResponseMeta `json:"-"`
+	// Raw is the decompressed response body, set when ClientConfig.RetainRawBody is true
+	Raw []byte `json:"-"`
+}
+
+// SearchPagination says whether a search has more results than were returned
+type SearchPagination struct {
+	HasMore      bool `json:"hasMore"`
+	TotalResults int  `json:"totalResults"`
 }
 
 // GetEventsKeysetResponse represents the response from the events keyset endpoint
@@ -217,4 +278,62 @@ type GetEventsKeysetResponse struct {
 	// NextCursor is the opaque cursor for fetching the next page. It is empty
 	// once the final page has been reached.
 	NextCursor string `json:"next_cursor"`
+	// Meta describes the HTTP exchange that produced this response
+	Meta *ResponseMeta `json:"-"`
+	// Raw is the decompressed response body, set when ClientConfig.RetainRawBody is true
+	Raw []byte `json:"-"`
+}
+
+// ResponseMeta carries transport-level details of a single API call, for debugging
+// and capacity planning
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// URL is the final request URL, after any redirects
+	URL string
+	// Latency is the time from sending the request to reading the full body
+	Latency time.Duration
+	// BytesReceived is the size of the body on the wire (before gzip)
+	BytesReceived int64
+	// BytesDecoded is the size of the body after gzip decompression
+	BytesDecoded int64
+	// CacheStatus says how the response cache handled the request, if enabled
+	CacheStatus CacheStatus
+	// Coalesced is true when the call shared another in-flight request's response
+	Coalesced bool
+}
+
+// RateLimitRemaining returns the remaining request allowance advertised by the
+// server, if any
+func (m *ResponseMeta) RateLimitRemaining() (int, bool) {
+	for _, key := range []string{"X-RateLimit-Remaining", "RateLimit-Remaining"} {
+		if value := m.Header.Get(key); value != "" {
+			remaining, err := strconv.Atoi(value)
+			return remaining, err == nil
+		}
+	}
+	return 0, false
+}
+
+// CFRay returns the Cloudflare request ID, which Polymarket support asks for when
+// reporting problems
+func (m *ResponseMeta) CFRay() string {
+	return m.Header.Get("CF-Ray")
+}
+
+// APIError is returned when the API answers with a status other than 200
+type APIError struct {
+	// Endpoint is the path that was requested, such as /events
+	Endpoint   string
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = "from the API"
+	}
+	return fmt.Sprintf("failed to fetch %s: %d %s - %s", endpoint, e.StatusCode, e.Status, string(e.Body))
 }
@@ -12,7 +12,7 @@ func TestEventFieldsParsing(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	client := NewClient(nil)
+	client := newIntegrationClient(t)
 
 	// Fetch events via pagination
 	response, err := client.GetEventsByPage(0, 5, true)
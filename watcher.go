@@ -0,0 +1,341 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWatchInterval      = 30 * time.Second
+	defaultWatchBuffer        = 64
+	defaultPriceMoveThreshold = 0.05
+	defaultLiquidityDrop      = 0.5
+)
+
+// NotificationKind is the type of change a Watcher reports
+type NotificationKind string
+
+const (
+	// NotificationEventListed is an event that newly matches the query
+	NotificationEventListed NotificationKind = "event_listed"
+	// NotificationEventRemoved is an event that no longer matches the query
+	NotificationEventRemoved NotificationKind = "event_removed"
+	// NotificationEventChanged carries the field-level changes to an event, when
+	// WatcherConfig.ReportChanges is set
+	NotificationEventChanged NotificationKind = "event_changed"
+	NotificationMarketAdded  NotificationKind = "market_added"
+	// NotificationMarketRemoved is a market that is no longer listed on its event.
+	// Market is the last snapshot seen.
+	NotificationMarketRemoved NotificationKind = "market_removed"
+	NotificationMarketClosed  NotificationKind = "market_closed"
+	// NotificationMarketResolved is a market whose outcome prices settled on a
+	// single winning outcome
+	NotificationMarketResolved NotificationKind = "market_resolved"
+	// NotificationPriceMoved is a last trade price move of at least
+	// WatcherConfig.PriceMoveThreshold
+	NotificationPriceMoved NotificationKind = "price_moved"
+	// NotificationLiquidityDropped is a liquidity fall of at least
+	// WatcherConfig.LiquidityDropThreshold
+	NotificationLiquidityDropped NotificationKind = "liquidity_dropped"
+)
+
+// Notification is one change seen by a Watcher
+type Notification struct {
+	Kind NotificationKind
+	Time time.Time
+	// Event is the current snapshot of the event, or the last one seen for
+	// NotificationEventRemoved
+	Event *Event
+	// Market is the market concerned, for market notifications
+	Market *Market
+	// Old and New are the values compared, for price and liquidity notifications
+	Old float64
+	New float64
+	// Changes lists the field-level changes, for NotificationEventChanged
+	Changes []Change
+}
+
+// WatchQuery fetches the set of events a Watcher tracks
+type WatchQuery func(ctx context.Context, api EventsAPI) ([]Event, error)
+
+// WatchEventIDs tracks the events with the given IDs
+func WatchEventIDs(ids ...int) WatchQuery {
+	return func(ctx context.Context, api EventsAPI) ([]Event, error) {
+		response, err := api.GetEventsByIDsContext(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return response.Events, nil
+	}
+}
+
+// WatchActiveEvents tracks every active event, walking all keyset pages each poll
+func WatchActiveEvents() WatchQuery {
+	return func(ctx context.Context, api EventsAPI) ([]Event, error) {
+		var events []Event
+		cursor := ""
+		for {
+			response, err := api.GetActiveEventsByKeysetPageContext(ctx, cursor, maxPageSize)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, response.Events...)
+			if response.NextCursor == "" {
+				return events, nil
+			}
+			cursor = response.NextCursor
+		}
+	}
+}
+
+// WatchTag tracks every active event tagged with slug, paging through QueryEvents
+// each poll. It is offset paginated, so a tag with more active events than the offset
+// cap fails with a 422 APIError.
+func WatchTag(slug string) WatchQuery {
+	return func(ctx context.Context, api EventsAPI) ([]Event, error) {
+		closed := false
+		query := EventQuery{
+			Limit:     maxPageSize,
+			Order:     "id",
+			Ascending: true,
+			TagSlug:   slug,
+			Closed:    &closed,
+		}
+
+		var events []Event
+		for {
+			response, err := api.QueryEventsContext(ctx, &query)
+			if err != nil {
+				return nil, err
+			}
+			if len(response.Events) == 0 {
+				return events, nil
+			}
+			events = append(events, response.Events...)
+			query.Offset += len(response.Events)
+		}
+	}
+}
+
+type WatcherConfig struct {
+	// Query selects the events to watch (required)
+	Query WatchQuery
+	// Interval is the time between polls (default 30s)
+	Interval time.Duration
+	// PriceMoveThreshold is the smallest absolute last trade price move reported
+	// (default 0.05)
+	PriceMoveThreshold float64
+	// LiquidityDropThreshold is the smallest fractional liquidity fall reported
+	// (default 0.5, a halving)
+	LiquidityDropThreshold float64
+	// ReportChanges also emits NotificationEventChanged with every field-level change
+	ReportChanges bool
+	// Differ configures the diff for ReportChanges (optional)
+	Differ *Differ
+	// NotifyExisting reports the events found by the first poll as listed. By default
+	// the first poll only records the baseline.
+	NotifyExisting bool
+	// Buffer is the capacity of the notification channel (default 64)
+	Buffer int
+	// DropWhenFull discards notifications while the channel is full instead of
+	// pausing polling until the consumer catches up. Dropped notifications are
+	// counted by Watcher.Dropped.
+	DropWhenFull bool
+	// OnError is called when a poll fails; the watcher keeps polling (optional)
+	OnError func(error)
+}
+
+// Watcher polls a query, compares each result with the previous one and emits typed
+// notifications on a channel
+type Watcher struct {
+	api           EventsAPI
+	config        WatcherConfig
+	notifications chan Notification
+	dropped       atomic.Int64
+	previous      map[string]Event
+}
+
+func NewWatcher(api EventsAPI, config *WatcherConfig) *Watcher {
+	if config == nil {
+		config = &WatcherConfig{}
+	}
+
+	watcher := &Watcher{api: api, config: *config}
+	if watcher.config.Interval <= 0 {
+		watcher.config.Interval = defaultWatchInterval
+	}
+	if watcher.config.PriceMoveThreshold <= 0 {
+		watcher.config.PriceMoveThreshold = defaultPriceMoveThreshold
+	}
+	if watcher.config.LiquidityDropThreshold <= 0 {
+		watcher.config.LiquidityDropThreshold = defaultLiquidityDrop
+	}
+	if watcher.config.Differ == nil {
+		watcher.config.Differ = &Differ{}
+	}
+	if watcher.config.Buffer <= 0 {
+		watcher.config.Buffer = defaultWatchBuffer
+	}
+	watcher.notifications = make(chan Notification, watcher.config.Buffer)
+	return watcher
+}
+
+// Notifications returns the channel notifications are sent on. It is closed when Run
+// returns.
+func (w *Watcher) Notifications() <-chan Notification {
+	return w.notifications
+}
+
+// Dropped returns how many notifications were discarded because of DropWhenFull
+func (w *Watcher) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Run polls until ctx is done. A Watcher can only be run once.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.notifications)
+
+	if w.config.Query == nil {
+		return errors.New("watcher has no query")
+	}
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context) {
+	events, err := w.config.Query(ctx, w.api)
+	if err != nil {
+		if ctx.Err() == nil && w.config.OnError != nil {
+			w.config.OnError(err)
+		}
+		return
+	}
+
+	now := time.Now()
+	first := w.previous == nil
+	current := make(map[string]Event, len(events))
+
+	for i := range events {
+		event := &events[i]
+		current[event.ID] = *event
+
+		previous, seen := w.previous[event.ID]
+		if !seen {
+			if !first || w.config.NotifyExisting {
+				w.emit(ctx, Notification{Kind: NotificationEventListed, Time: now, Event: event})
+			}
+			continue
+		}
+		w.compare(ctx, now, &previous, event)
+	}
+
+	for id, previous := range w.previous {
+		if _, ok := current[id]; !ok {
+			w.emit(ctx, Notification{Kind: NotificationEventRemoved, Time: now, Event: &previous})
+		}
+	}
+
+	w.previous = current
+}
+
+// compare emits the notifications for the changes between two snapshots of an event
+func (w *Watcher) compare(ctx context.Context, now time.Time, previous, event *Event) {
+	previousMarkets := make(map[string]Market, len(previous.Markets))
+	for _, market := range previous.Markets {
+		previousMarkets[market.ID] = market
+	}
+	currentMarkets := make(map[string]bool, len(event.Markets))
+	for _, market := range event.Markets {
+		currentMarkets[market.ID] = true
+	}
+
+	for i := range event.Markets {
+		market := &event.Markets[i]
+		notify := func(kind NotificationKind, old, new float64) {
+			w.emit(ctx, Notification{Kind: kind, Time: now, Event: event, Market: market, Old: old, New: new})
+		}
+
+		old, ok := previousMarkets[market.ID]
+		if !ok {
+			notify(NotificationMarketAdded, 0, 0)
+			continue
+		}
+
+		if market.Closed && !old.Closed {
+			notify(NotificationMarketClosed, 0, 0)
+		}
+		if resolved(market) && !resolved(&old) {
+			notify(NotificationMarketResolved, 0, 0)
+		}
+		if math.Abs(market.LastTradePrice-old.LastTradePrice) >= w.config.PriceMoveThreshold {
+			notify(NotificationPriceMoved, old.LastTradePrice, market.LastTradePrice)
+		}
+		if old.LiquidityNum > 0 && (old.LiquidityNum-market.LiquidityNum)/old.LiquidityNum >= w.config.LiquidityDropThreshold {
+			notify(NotificationLiquidityDropped, old.LiquidityNum, market.LiquidityNum)
+		}
+	}
+
+	for i := range previous.Markets {
+		if market := &previous.Markets[i]; !currentMarkets[market.ID] {
+			w.emit(ctx, Notification{Kind: NotificationMarketRemoved, Time: now, Event: event, Market: market})
+		}
+	}
+
+	if w.config.ReportChanges {
+		if changes := w.config.Differ.Diff(*previous, *event); len(changes) > 0 {
+			w.emit(ctx, Notification{Kind: NotificationEventChanged, Time: now, Event: event, Changes: changes})
+		}
+	}
+}
+
+func (w *Watcher) emit(ctx context.Context, notification Notification) {
+	if w.config.DropWhenFull {
+		select {
+		case w.notifications <- notification:
+		default:
+			w.dropped.Add(1)
+		}
+		return
+	}
+
+	select {
+	case w.notifications <- notification:
+	case <-ctx.Done():
+	}
+}
+
+// resolved reports whether a market's outcome prices settled on one outcome, such
+// as ["1", "0"]
+func resolved(market *Market) bool {
+	prices := strings.Trim(market.OutcomePrices, "[]")
+	if prices == "" {
+		return false
+	}
+
+	winners := 0
+	for _, price := range strings.Split(prices, ",") {
+		switch strings.Trim(strings.TrimSpace(price), `"`) {
+		case "1", "1.0":
+			winners++
+		case "0", "0.0":
+		default:
+			return false
+		}
+	}
+	return winners == 1
+}
@@ -0,0 +1,242 @@
+package polymarket_gamma
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshots is a WatchQuery returning successive snapshots, repeating the last one
+type snapshots struct {
+	mu    sync.Mutex
+	polls [][]Event
+	calls int
+}
+
+func (s *snapshots) query(ctx context.Context, api EventsAPI) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	polls := s.polls[min(s.calls, len(s.polls)-1)]
+	s.calls++
+	if polls == nil {
+		return nil, errors.New("poll failed")
+	}
+	return polls, nil
+}
+
+func watchedMarket(id string, price, liquidity float64) Market {
+	market := mockMarket(id)
+	market.LastTradePrice = price
+	market.LiquidityNum = liquidity
+	market.OutcomePrices = `["0.5", "0.5"]`
+	return market
+}
+
+func watchedEvent(id string, markets ...Market) Event {
+	event := mockEvent(id)
+	event.Markets = markets
+	return event
+}
+
+func collect(t *testing.T, watcher *Watcher, n int) []Notification {
+	var notifications []Notification
+	timeout := time.After(time.Second)
+	for len(notifications) < n {
+		select {
+		case notification := <-watcher.Notifications():
+			notifications = append(notifications, notification)
+		case <-timeout:
+			t.Fatalf("got %d notifications, want %d", len(notifications), n)
+		}
+	}
+	return notifications
+}
+
+func TestWatcherEmitsTypedNotifications(t *testing.T) {
+	resolvedMarket := watchedMarket("m2", 0.9, 100)
+	resolvedMarket.Closed = true
+	resolvedMarket.OutcomePrices = `["1", "0"]`
+
+	query := &snapshots{polls: [][]Event{
+		{watchedEvent("1", watchedMarket("m1", 0.5, 1000), watchedMarket("m2", 0.9, 100))},
+		nil,
+		{
+			watchedEvent("1", watchedMarket("m1", 0.6, 400), resolvedMarket, watchedMarket("m3", 0.1, 10)),
+			watchedEvent("2"),
+		},
+		{watchedEvent("2")},
+	}}
+
+	var errs []error
+	watcher := NewWatcher(nil, &WatcherConfig{
+		Query:    query.query,
+		Interval: time.Millisecond,
+		OnError:  func(err error) { errs = append(errs, err) },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+
+	notifications := collect(t, watcher, 7)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	type summary struct {
+		Kind   NotificationKind
+		Event  string
+		Market string
+	}
+	var got []summary
+	for _, n := range notifications {
+		s := summary{Kind: n.Kind, Event: n.Event.ID}
+		if n.Market != nil {
+			s.Market = n.Market.ID
+		}
+		got = append(got, s)
+	}
+	assert.Equal(t, []summary{
+		{NotificationPriceMoved, "1", "m1"},
+		{NotificationLiquidityDropped, "1", "m1"},
+		{NotificationMarketClosed, "1", "m2"},
+		{NotificationMarketResolved, "1", "m2"},
+		{NotificationMarketAdded, "1", "m3"},
+		{NotificationEventListed, "2", ""},
+		{NotificationEventRemoved, "1", ""},
+	}, got)
+
+	assert.InDelta(t, 0.5, notifications[0].Old, 1e-9)
+	assert.InDelta(t, 0.6, notifications[0].New, 1e-9)
+	assert.Equal(t, []error{errors.New("poll failed")}, errs)
+}
+
+func TestWatcherReportsRemovedMarkets(t *testing.T) {
+	query := &snapshots{polls: [][]Event{
+		{watchedEvent("1", watchedMarket("m1", 0.5, 100), watchedMarket("m2", 0.5, 100))},
+		{watchedEvent("1", watchedMarket("m2", 0.5, 100), watchedMarket("m3", 0.5, 100))},
+	}}
+	watcher := NewWatcher(nil, &WatcherConfig{Query: query.query, Interval: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	notifications := collect(t, watcher, 2)
+	assert.Equal(t, NotificationMarketAdded, notifications[0].Kind)
+	assert.Equal(t, "m3", notifications[0].Market.ID)
+	assert.Equal(t, NotificationMarketRemoved, notifications[1].Kind)
+	assert.Equal(t, "m1", notifications[1].Market.ID)
+	assert.Equal(t, "1", notifications[1].Event.ID)
+}
+
+// taggedAPI answers QueryEvents from its events, filtering by tag slug and paging
+// two events at a time
+type taggedAPI struct {
+	EventsAPI
+	events  []Event
+	queries []EventQuery
+}
+
+func (a *taggedAPI) QueryEventsContext(ctx context.Context, query *EventQuery) (*GetEventsResponse, error) {
+	a.queries = append(a.queries, *query)
+
+	var matched []Event
+	for _, event := range a.events {
+		if slices.ContainsFunc(event.Tags, func(tag Tag) bool { return tag.Slug == query.TagSlug }) {
+			matched = append(matched, event)
+		}
+	}
+	page := matched[min(query.Offset, len(matched)):min(query.Offset+2, len(matched))]
+	return &GetEventsResponse{Events: page}, nil
+}
+
+func TestWatchTag(t *testing.T) {
+	var events []Event
+	for i := 1; i <= 5; i++ {
+		event := mockEvent(strconv.Itoa(i))
+		event.Tags = []Tag{{ID: "1", Slug: "sports"}}
+		if i != 4 {
+			event.Tags = append(event.Tags, Tag{ID: "2", Slug: "nba"})
+		}
+		events = append(events, event)
+	}
+	api := &taggedAPI{events: events}
+
+	watched, err := WatchTag("nba")(context.Background(), api)
+	require.NoError(t, err)
+	var ids []string
+	for _, event := range watched {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "3", "5"}, ids)
+
+	require.Len(t, api.queries, 3)
+	assert.Equal(t, "nba", api.queries[0].TagSlug)
+	require.NotNil(t, api.queries[0].Closed)
+	assert.False(t, *api.queries[0].Closed)
+	assert.Equal(t, []int{0, 2, 4}, []int{api.queries[0].Offset, api.queries[1].Offset, api.queries[2].Offset})
+}
+
+func TestWatcherReportChanges(t *testing.T) {
+	original := watchedEvent("1")
+	renamed := original
+	renamed.Title = "Renamed"
+
+	query := &snapshots{polls: [][]Event{{original}, {renamed}}}
+	watcher := NewWatcher(nil, &WatcherConfig{
+		Query:          query.query,
+		Interval:       time.Millisecond,
+		ReportChanges:  true,
+		NotifyExisting: true,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	notifications := collect(t, watcher, 2)
+	assert.Equal(t, NotificationEventListed, notifications[0].Kind)
+	assert.Equal(t, NotificationEventChanged, notifications[1].Kind)
+	assert.Equal(t, []Change{{Path: "title", Kind: ChangeModified, Old: "Test Event", New: "Renamed"}}, notifications[1].Changes)
+}
+
+func TestWatcherBackpressure(t *testing.T) {
+	var polls [][]Event
+	for i := 0; i < 10; i++ {
+		polls = append(polls, []Event{watchedEvent("1", watchedMarket("m1", float64(i%2), 0))})
+	}
+
+	query := &snapshots{polls: polls}
+	watcher := NewWatcher(nil, &WatcherConfig{
+		Query:        query.query,
+		Interval:     time.Millisecond,
+		Buffer:       2,
+		DropWhenFull: true,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watcher.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return watcher.Dropped() > 0 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	// The channel is closed once Run returns, after the buffered notifications
+	count := 0
+	for range watcher.Notifications() {
+		count++
+	}
+	assert.Equal(t, 2, count)
+}
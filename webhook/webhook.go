@@ -0,0 +1,533 @@
+// Package webhook delivers Watcher notifications to HTTP subscribers.
+//
+// Each notification is POSTed as JSON. When the subscriber has a secret, the request
+// is signed with HMAC-SHA256 in the X-Gamma-Signature header ("sha256=<hex>"),
+// computed over the X-Gamma-Timestamp header (Unix seconds), a ".", and the raw
+// body. Receivers should check it with Verify and reject timestamps more than a few
+// minutes old, so a captured request cannot be replayed later. Failed deliveries are
+// retried with exponential backoff, honouring a capped Retry-After on 429s and 503s,
+// and once attempts run out they are appended to a dead-letter NDJSON file so they
+// can be replayed.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = 500 * time.Millisecond
+	defaultMaxRetry    = 30 * time.Second
+	defaultTimeout     = 10 * time.Second
+
+	// SignatureHeader carries the HMAC-SHA256 signature of the timestamp and body
+	SignatureHeader = "X-Gamma-Signature"
+	// TimestampHeader carries the Unix time the request was signed at
+	TimestampHeader = "X-Gamma-Timestamp"
+	// DeliveryHeader carries a unique ID per notification, shared by its retries
+	DeliveryHeader = "X-Gamma-Delivery"
+)
+
+// Filter limits which notifications a subscriber receives. Empty fields match
+// everything; within a field any value may match.
+type Filter struct {
+	Kinds       []polymarket_gamma.NotificationKind
+	TagSlugs    []string
+	SeriesSlugs []string
+	Categories  []string
+	// MinVolume is the smallest event volume delivered
+	MinVolume float64
+}
+
+type Subscriber struct {
+	Name   string
+	URL    string
+	Secret string
+	Filter Filter
+}
+
+type Config struct {
+	Subscribers []Subscriber
+	// HTTPClient sends the requests (default: a client with a 10s timeout)
+	HTTPClient *http.Client
+	// MaxAttempts is the number of delivery attempts per subscriber (default 5)
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubling each attempt (default
+	// 500ms). A Retry-After header on a 429 or 503 replaces it for the next attempt.
+	Backoff time.Duration
+	// MaxRetryAfter caps the wait a subscriber's Retry-After can ask for (default
+	// 30s), so one subscriber can't hold up Dispatch indefinitely
+	MaxRetryAfter time.Duration
+	// DeadLetterPath is the NDJSON file undeliverable notifications are appended to
+	// (optional)
+	DeadLetterPath string
+}
+
+// Payload is the JSON body POSTed to subscribers. Old and New are set for price and
+// liquidity notifications only, so a move to or from zero keeps both fields.
+type Payload struct {
+	ID       string                            `json:"id"`
+	Kind     polymarket_gamma.NotificationKind `json:"kind"`
+	Time     time.Time                         `json:"time"`
+	EventID  string                            `json:"event_id,omitempty"`
+	MarketID string                            `json:"market_id,omitempty"`
+	Event    *polymarket_gamma.Event           `json:"event,omitempty"`
+	Market   *polymarket_gamma.Market          `json:"market,omitempty"`
+	Old      *float64                          `json:"old,omitempty"`
+	New      *float64                          `json:"new,omitempty"`
+	Changes  []PayloadChange                   `json:"changes,omitempty"`
+}
+
+type PayloadChange struct {
+	Path string                      `json:"path"`
+	Kind polymarket_gamma.ChangeKind `json:"kind"`
+	Old  any                         `json:"old,omitempty"`
+	New  any                         `json:"new,omitempty"`
+}
+
+// Stats counts deliveries to one subscriber
+type Stats struct {
+	Delivered    int
+	Failed       int
+	Retries      int
+	Filtered     int
+	LastError    string
+	LastLatency  time.Duration
+	LastDelivery time.Time
+}
+
+// DeadLetter is one line of the dead-letter file
+type DeadLetter struct {
+	Subscriber string          `json:"subscriber"`
+	URL        string          `json:"url"`
+	Time       time.Time       `json:"time"`
+	Attempts   int             `json:"attempts"`
+	Error      string          `json:"error"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Dispatcher delivers notifications to every matching subscriber
+type Dispatcher struct {
+	config     Config
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	stats map[string]*Stats
+
+	deadLetterMu sync.Mutex
+	deadLetter   *os.File
+}
+
+func New(config *Config) (*Dispatcher, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	dispatcher := &Dispatcher{config: *config, stats: map[string]*Stats{}}
+	if dispatcher.config.MaxAttempts <= 0 {
+		dispatcher.config.MaxAttempts = defaultMaxAttempts
+	}
+	if dispatcher.config.Backoff <= 0 {
+		dispatcher.config.Backoff = defaultBackoff
+	}
+	if dispatcher.config.MaxRetryAfter <= 0 {
+		dispatcher.config.MaxRetryAfter = defaultMaxRetry
+	}
+
+	dispatcher.httpClient = config.HTTPClient
+	if dispatcher.httpClient == nil {
+		dispatcher.httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+
+	for _, subscriber := range config.Subscribers {
+		if subscriber.URL == "" {
+			return nil, fmt.Errorf("subscriber %q has no URL", subscriber.Name)
+		}
+		dispatcher.stats[subscriber.Name] = &Stats{}
+	}
+
+	if config.DeadLetterPath != "" {
+		file, err := os.OpenFile(config.DeadLetterPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dead-letter file: %w", err)
+		}
+		dispatcher.deadLetter = file
+	}
+
+	return dispatcher, nil
+}
+
+// Close closes the dead-letter file
+func (d *Dispatcher) Close() error {
+	if d.deadLetter == nil {
+		return nil
+	}
+	return d.deadLetter.Close()
+}
+
+// Run dispatches every notification from notifications until the channel is closed
+// or ctx is done
+func (d *Dispatcher) Run(ctx context.Context, notifications <-chan polymarket_gamma.Notification) error {
+	for {
+		select {
+		case notification, ok := <-notifications:
+			if !ok {
+				return nil
+			}
+			if err := d.Dispatch(ctx, notification); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Dispatch delivers notification to every matching subscriber concurrently and
+// waits for them. Undeliverable notifications are dead-lettered rather than
+// returned; the error reports only a failure to encode or dead-letter.
+func (d *Dispatcher) Dispatch(ctx context.Context, notification polymarket_gamma.Notification) error {
+	deliveryID := newDeliveryID()
+	body, err := json.Marshal(newPayload(deliveryID, notification))
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	var subscribers []Subscriber
+	for _, subscriber := range d.config.Subscribers {
+		if !subscriber.Filter.Match(notification) {
+			d.update(subscriber.Name, func(s *Stats) { s.Filtered++ })
+			continue
+		}
+		subscribers = append(subscribers, subscriber)
+	}
+
+	_, err = d.deliverAll(ctx, subscribers, deliveryID, body)
+	return err
+}
+
+// Send delivers v as JSON to every subscriber, ignoring their filters, with the same
+// signing, retries and dead-lettering as Dispatch. Unlike Dispatch it returns an
+// error when a subscriber did not receive v, even if it was dead-lettered, so the
+// caller can try again later.
+func (d *Dispatcher) Send(ctx context.Context, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	undelivered, err := d.deliverAll(ctx, d.config.Subscribers, newDeliveryID(), body)
+	if err != nil {
+		return err
+	}
+	if len(undelivered) > 0 {
+		return fmt.Errorf("failed to deliver to %s", strings.Join(undelivered, ", "))
+	}
+	return nil
+}
+
+// deliverAll delivers body to subscribers concurrently and returns the names of those
+// it could not reach
+func (d *Dispatcher) deliverAll(ctx context.Context, subscribers []Subscriber, deliveryID string, body []byte) ([]string, error) {
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		undelivered []string
+		firstErr    error
+	)
+	for _, subscriber := range subscribers {
+		wg.Add(1)
+		go func(subscriber Subscriber) {
+			defer wg.Done()
+			delivered, err := d.deliver(ctx, subscriber, deliveryID, body)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !delivered {
+				undelivered = append(undelivered, subscriber.Name)
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(subscriber)
+	}
+	wg.Wait()
+
+	sort.Strings(undelivered)
+	return undelivered, firstErr
+}
+
+// Stats returns a copy of the delivery counters, keyed by subscriber name
+func (d *Dispatcher) Stats() map[string]Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := make(map[string]Stats, len(d.stats))
+	for name, s := range d.stats {
+		stats[name] = *s
+	}
+	return stats
+}
+
+// deliver POSTs body to subscriber, retrying transport errors, 429s and 5xx
+// responses, and dead-letters it when every attempt fails. It reports whether body
+// was delivered; the error is only for a failure to dead-letter.
+func (d *Dispatcher) deliver(ctx context.Context, subscriber Subscriber, deliveryID string, body []byte) (bool, error) {
+	backoff := d.config.Backoff
+	wait := backoff
+
+	var lastErr error
+	attempts := 0
+	for attempts < d.config.MaxAttempts {
+		if attempts > 0 {
+			d.update(subscriber.Name, func(s *Stats) { s.Retries++ })
+			if err := sleep(ctx, wait); err != nil {
+				lastErr = err
+				break
+			}
+			backoff *= 2
+		}
+
+		start := time.Now()
+		retry, retryAfter, err := d.post(ctx, subscriber, deliveryID, body)
+		attempts++
+		if err == nil {
+			d.update(subscriber.Name, func(s *Stats) {
+				s.Delivered++
+				s.LastLatency = time.Since(start)
+				s.LastDelivery = time.Now()
+			})
+			return true, nil
+		}
+
+		lastErr = err
+		if !retry {
+			break
+		}
+		wait = backoff
+		if retryAfter > 0 {
+			wait = min(retryAfter, d.config.MaxRetryAfter)
+		}
+	}
+
+	d.update(subscriber.Name, func(s *Stats) {
+		s.Failed++
+		s.LastError = lastErr.Error()
+	})
+	return false, d.deadLetterWrite(DeadLetter{
+		Subscriber: subscriber.Name,
+		URL:        subscriber.URL,
+		Time:       time.Now().UTC(),
+		Attempts:   attempts,
+		Error:      lastErr.Error(),
+		Payload:    body,
+	})
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying,
+// and after how long if the subscriber said so with Retry-After
+func (d *Dispatcher) post(ctx context.Context, subscriber Subscriber, deliveryID string, body []byte) (bool, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscriber.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(TimestampHeader, timestamp)
+	if subscriber.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(subscriber.Secret, timestamp, body))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, 0, fmt.Errorf("failed to deliver: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, 0, nil
+	}
+	err = fmt.Errorf("failed to deliver: %s", resp.Status)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return true, retryAfter(resp.Header.Get("Retry-After"), time.Now()), err
+	}
+	return resp.StatusCode >= 500, 0, err
+}
+
+// retryAfter parses a Retry-After value in seconds or as an HTTP date, returning zero
+// if it is missing or invalid
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+func (d *Dispatcher) deadLetterWrite(letter DeadLetter) error {
+	if d.deadLetter == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+
+	d.deadLetterMu.Lock()
+	defer d.deadLetterMu.Unlock()
+	if _, err := d.deadLetter.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return nil
+}
+
+func (d *Dispatcher) update(name string, fn func(*Stats)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, ok := d.stats[name]
+	if !ok {
+		s = &Stats{}
+		d.stats[name] = s
+	}
+	fn(s)
+}
+
+// Match reports whether notification passes the filter
+func (f Filter) Match(notification polymarket_gamma.Notification) bool {
+	if len(f.Kinds) > 0 && !slices.Contains(f.Kinds, notification.Kind) {
+		return false
+	}
+
+	event := notification.Event
+	if event == nil {
+		return len(f.TagSlugs) == 0 && len(f.SeriesSlugs) == 0 && len(f.Categories) == 0 && f.MinVolume == 0
+	}
+
+	if event.Volume < f.MinVolume {
+		return false
+	}
+	if len(f.Categories) > 0 && !slices.Contains(f.Categories, event.Category) {
+		return false
+	}
+	if len(f.TagSlugs) > 0 && !slices.ContainsFunc(event.Tags, func(tag polymarket_gamma.Tag) bool {
+		return slices.Contains(f.TagSlugs, tag.Slug)
+	}) {
+		return false
+	}
+	if len(f.SeriesSlugs) > 0 && !slices.Contains(f.SeriesSlugs, event.SeriesSlug) && !slices.ContainsFunc(event.Series, func(series polymarket_gamma.Series) bool {
+		return slices.Contains(f.SeriesSlugs, series.Slug)
+	}) {
+		return false
+	}
+	return true
+}
+
+// Sign returns the signature header value for a request with the given
+// TimestampHeader value and body
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is a valid signature of timestamp and body, for
+// receivers. It does not check the timestamp's age; receivers should also reject
+// old timestamps to stop replays.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// ReadDeadLetters reads every entry from a dead-letter file
+func ReadDeadLetters(path string) ([]DeadLetter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letters: %w", err)
+	}
+
+	var letters []DeadLetter
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var letter DeadLetter
+		err := decoder.Decode(&letter)
+		if errors.Is(err, io.EOF) {
+			return letters, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dead letters: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+}
+
+func newPayload(id string, notification polymarket_gamma.Notification) Payload {
+	payload := Payload{
+		ID:     id,
+		Kind:   notification.Kind,
+		Time:   notification.Time,
+		Event:  notification.Event,
+		Market: notification.Market,
+	}
+	switch notification.Kind {
+	case polymarket_gamma.NotificationPriceMoved, polymarket_gamma.NotificationLiquidityDropped:
+		payload.Old = &notification.Old
+		payload.New = &notification.New
+	}
+	if notification.Event != nil {
+		payload.EventID = notification.Event.ID
+	}
+	if notification.Market != nil {
+		payload.MarketID = notification.Market.ID
+	}
+	for _, change := range notification.Changes {
+		payload.Changes = append(payload.Changes, PayloadChange(change))
+	}
+	return payload
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newDeliveryID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
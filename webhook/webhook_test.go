@@ -0,0 +1,248 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	polymarket_gamma "github.com/CalderWhite/polymarket-gamma-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receiver struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	payloads   []Payload
+	signatures []bool
+	deliveries []string
+	hits       atomic.Int32
+}
+
+// newReceiver answers the first failures requests with status, then 204
+func newReceiver(t *testing.T, secret string, failures int32, status int) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.hits.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		var payload Payload
+		json.Unmarshal(body, &payload)
+
+		r.mu.Lock()
+		r.payloads = append(r.payloads, payload)
+		r.signatures = append(r.signatures, Verify(secret, req.Header.Get(TimestampHeader), body, req.Header.Get(SignatureHeader)))
+		r.deliveries = append(r.deliveries, req.Header.Get(DeliveryHeader))
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func notification(kind polymarket_gamma.NotificationKind, category string, tags ...string) polymarket_gamma.Notification {
+	event := &polymarket_gamma.Event{ID: "1", Title: "Event", Category: category, Volume: 5000}
+	for _, tag := range tags {
+		event.Tags = append(event.Tags, polymarket_gamma.Tag{ID: tag, Slug: tag})
+	}
+	return polymarket_gamma.Notification{
+		Kind:   kind,
+		Time:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Event:  event,
+		Market: &polymarket_gamma.Market{ID: "m1"},
+		Old:    0.4,
+		New:    0.6,
+	}
+}
+
+func TestDispatchSignsAndFilters(t *testing.T) {
+	sports := newReceiver(t, "sports-secret", 0, 0)
+	politics := newReceiver(t, "politics-secret", 0, 0)
+
+	dispatcher, err := New(&Config{Subscribers: []Subscriber{
+		{Name: "sports", URL: sports.URL, Secret: "sports-secret", Filter: Filter{Categories: []string{"Sports"}}},
+		{Name: "politics", URL: politics.URL, Secret: "politics-secret", Filter: Filter{TagSlugs: []string{"politics"}, MinVolume: 1000}},
+	}})
+	require.NoError(t, err)
+	defer dispatcher.Close()
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), notification(polymarket_gamma.NotificationPriceMoved, "Sports")))
+	require.NoError(t, dispatcher.Dispatch(context.Background(), notification(polymarket_gamma.NotificationMarketClosed, "Politics", "politics")))
+
+	require.Len(t, sports.payloads, 1)
+	payload := sports.payloads[0]
+	assert.Equal(t, polymarket_gamma.NotificationPriceMoved, payload.Kind)
+	assert.Equal(t, "1", payload.EventID)
+	assert.Equal(t, "m1", payload.MarketID)
+	require.NotNil(t, payload.New)
+	assert.Equal(t, 0.6, *payload.New)
+	assert.Equal(t, sports.deliveries[0], payload.ID)
+	assert.Equal(t, []bool{true}, sports.signatures)
+
+	require.Len(t, politics.payloads, 1)
+	assert.Equal(t, polymarket_gamma.NotificationMarketClosed, politics.payloads[0].Kind)
+	assert.Nil(t, politics.payloads[0].Old, "only price and liquidity notifications carry values")
+	assert.Equal(t, []bool{true}, politics.signatures)
+
+	stats := dispatcher.Stats()
+	assert.Equal(t, 1, stats["sports"].Delivered)
+	assert.Equal(t, 1, stats["sports"].Filtered)
+	assert.Equal(t, 1, stats["politics"].Delivered)
+	assert.Equal(t, 1, stats["politics"].Filtered)
+}
+
+func TestDispatchRetriesThenDeadLetters(t *testing.T) {
+	flaky := newReceiver(t, "", 2, http.StatusServiceUnavailable)
+	down := newReceiver(t, "", 100, http.StatusBadGateway)
+	rejecting := newReceiver(t, "", 100, http.StatusBadRequest)
+	deadLetters := filepath.Join(t.TempDir(), "dead.ndjson")
+
+	dispatcher, err := New(&Config{
+		Subscribers: []Subscriber{
+			{Name: "flaky", URL: flaky.URL},
+			{Name: "down", URL: down.URL},
+			{Name: "rejecting", URL: rejecting.URL},
+		},
+		MaxAttempts:    3,
+		Backoff:        time.Millisecond,
+		DeadLetterPath: deadLetters,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), notification(polymarket_gamma.NotificationEventListed, "Sports")))
+	require.NoError(t, dispatcher.Close())
+
+	stats := dispatcher.Stats()
+	assert.Equal(t, 1, stats["flaky"].Delivered)
+	assert.Equal(t, 2, stats["flaky"].Retries)
+	assert.Equal(t, 1, stats["down"].Failed)
+	assert.Equal(t, "failed to deliver: 502 Bad Gateway", stats["down"].LastError)
+	assert.Equal(t, int32(3), down.hits.Load())
+	assert.Equal(t, int32(1), rejecting.hits.Load(), "4xx responses are not retried")
+
+	letters, err := ReadDeadLetters(deadLetters)
+	require.NoError(t, err)
+	require.Len(t, letters, 2)
+
+	bySubscriber := map[string]DeadLetter{}
+	for _, letter := range letters {
+		bySubscriber[letter.Subscriber] = letter
+	}
+	assert.Equal(t, 3, bySubscriber["down"].Attempts)
+	assert.Equal(t, 1, bySubscriber["rejecting"].Attempts)
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(bySubscriber["down"].Payload, &payload))
+	assert.Equal(t, polymarket_gamma.NotificationEventListed, payload.Kind)
+}
+
+func TestRunConsumesWatcherChannel(t *testing.T) {
+	r := newReceiver(t, "", 0, 0)
+	dispatcher, err := New(&Config{Subscribers: []Subscriber{
+		{Name: "all", URL: r.URL, Filter: Filter{Kinds: []polymarket_gamma.NotificationKind{polymarket_gamma.NotificationMarketResolved}}},
+	}})
+	require.NoError(t, err)
+
+	notifications := make(chan polymarket_gamma.Notification, 2)
+	notifications <- notification(polymarket_gamma.NotificationMarketResolved, "Sports")
+	notifications <- notification(polymarket_gamma.NotificationPriceMoved, "Sports")
+	close(notifications)
+
+	require.NoError(t, dispatcher.Run(context.Background(), notifications))
+	assert.Len(t, r.payloads, 1)
+
+	_, err = New(&Config{Subscribers: []Subscriber{{Name: "broken"}}})
+	assert.ErrorContains(t, err, `subscriber "broken" has no URL`)
+}
+
+func TestSignatureCoversTimestamp(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("s3cret", "1790000000", body)
+
+	assert.True(t, Verify("s3cret", "1790000000", body, signature))
+	assert.False(t, Verify("s3cret", "1790000999", body, signature), "a replay with a new timestamp fails")
+	assert.False(t, Verify("other", "1790000000", body, signature))
+}
+
+func TestPayloadKeepsZeroValues(t *testing.T) {
+	moved := notification(polymarket_gamma.NotificationPriceMoved, "Sports")
+	moved.Old, moved.New = 0.3, 0
+
+	body, err := json.Marshal(newPayload("d1", moved))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"old":0.3,"new":0`)
+
+	body, err = json.Marshal(newPayload("d2", notification(polymarket_gamma.NotificationEventListed, "Sports")))
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), `"old"`)
+}
+
+func TestDeliverHonoursRetryAfter(t *testing.T) {
+	var hits atomic.Int32
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher, err := New(&Config{
+		Subscribers: []Subscriber{{Name: "limited", URL: server.URL}},
+		Backoff:     time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), notification(polymarket_gamma.NotificationEventListed, "Sports")))
+	require.Len(t, times, 2)
+	assert.GreaterOrEqual(t, times[1].Sub(times[0]), 900*time.Millisecond, "waited for Retry-After, not the 1ms backoff")
+	assert.Equal(t, 1, dispatcher.Stats()["limited"].Delivered)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 30*time.Second, retryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, retryAfter("soon", now))
+}
+
+func TestDeliverCapsRetryAfterOn503(t *testing.T) {
+	var hits atomic.Int32
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher, err := New(&Config{
+		Subscribers:   []Subscriber{{Name: "unavailable", URL: server.URL}},
+		Backoff:       time.Millisecond,
+		MaxRetryAfter: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), notification(polymarket_gamma.NotificationEventListed, "Sports")))
+	require.Len(t, times, 2)
+	waited := times[1].Sub(times[0])
+	assert.GreaterOrEqual(t, waited, 150*time.Millisecond, "waited for the 503's Retry-After, not the 1ms backoff")
+	assert.Less(t, waited, 5*time.Second, "Retry-After was capped at MaxRetryAfter")
+	assert.Equal(t, 1, dispatcher.Stats()["unavailable"].Delivered)
+}